@@ -0,0 +1,15 @@
+data "gitlab_api" "submodules" {
+  path = "projects/foo%2Fbar/repository/submodules"
+}
+
+data "gitlab_api" "issues" {
+  path = "projects/42/issues"
+  query = {
+    state    = "opened"
+    per_page = 100
+  }
+}
+
+output "open_issues" {
+  value = data.gitlab_api.issues.response_headers["X-Total"]
+}
@@ -0,0 +1,22 @@
+data "gitlab_graphql_query" "work_items" {
+  query = <<-EOT
+    query($fullPath: ID!, $after: String) {
+      project(fullPath: $fullPath) {
+        workItems(after: $after) {
+          nodes { iid title state }
+          pageInfo { hasNextPage endCursor }
+        }
+      }
+    }
+  EOT
+
+  variables = {
+    fullPath = "foo/bar"
+  }
+
+  paginate = "project.workItems"
+}
+
+output "open_work_items" {
+  value = [for item in jsondecode(data.gitlab_graphql_query.work_items.data).project.workItems.nodes : item.title if item.state == "OPEN"]
+}
@@ -0,0 +1,12 @@
+resource "gitlab_api_object" "hook" {
+  create_path = "projects/42/hooks"
+  read_path   = "projects/42/hooks/{id}"
+
+  body = jsonencode({
+    url         = "https://example.com/hook"
+    push_events = true
+  })
+
+  # Show a diff if the URL of the hook is changed outside of Terraform
+  drift_detection_keys = ["url"]
+}
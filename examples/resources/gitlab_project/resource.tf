@@ -27,3 +27,12 @@ resource "gitlab_project" "peters_repo" {
   description  = "This is a description"
   namespace_id = data.gitlab_user.peter_parker.namespace_id
 }
+
+# Fork a project into a group, with only its default branch
+resource "gitlab_project" "example_fork" {
+  name                   = "example-fork"
+  path                   = "example-fork"
+  forked_from_project_id = gitlab_project.example.id
+  fork_namespace         = "my-group"
+  fork_branches          = "main"
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// sendAPIRequest sends a request to an arbitrary path of the GitLab REST API, e.g. `projects/42/hooks`,
+// and returns the raw response body. The path is relative to the configured base URL.
+// The request uses the same transport as every other request of the client, thus the same authentication, TLS settings, retries and logging.
+func sendAPIRequest(ctx context.Context, client *gitlab.Client, method, path string, query url.Values, body json.RawMessage) ([]byte, *gitlab.Response, error) {
+	var opt interface{}
+	if len(body) > 0 {
+		opt = body
+	}
+
+	request, err := client.NewRequest(method, strings.TrimPrefix(path, "/"), opt, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(query) > 0 {
+		request.URL.RawQuery = query.Encode()
+	}
+
+	var response bytes.Buffer
+	resp, err := client.Do(request, &response)
+	if err != nil {
+		return nil, resp, err
+	}
+	return response.Bytes(), resp, nil
+}
+
+// expandAPIPath replaces the `{id}` placeholder in the given path with the escaped object ID.
+func expandAPIPath(path, id string) string {
+	return strings.ReplaceAll(path, "{id}", url.PathEscape(id))
+}
+
+// flattenAPIResponseHeaders converts the given headers to a map, with multiple values of a header joined by a comma.
+func flattenAPIResponseHeaders(resp *gitlab.Response) map[string]string {
+	headers := make(map[string]string, len(resp.Header))
+	for name, values := range resp.Header {
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// avatarSchema returns the `avatar`, `avatar_hash` and `avatar_url` attributes for resources of the given kind of object.
+// The resources must use customizeDiffAvatarHash in their CustomizeDiff and update the avatar with updateAvatar.
+func avatarSchema(object string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"avatar": {
+			Description: fmt.Sprintf("A local path to the avatar image to upload for the %s. **Note**: not available for imported resources.", object),
+			Type:        schema.TypeString,
+			Optional:    true,
+		},
+		"avatar_hash": {
+			Description:  "The hash of the avatar image. Use `filesha256(\"path/to/avatar.png\")` whenever possible. **Note**: this is used to trigger an update of the avatar. If it's not given, but an avatar is given, the avatar will be updated each time.",
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			RequiredWith: []string{"avatar"},
+		},
+		"avatar_url": {
+			Description: fmt.Sprintf("The URL of the avatar image of the %s.", object),
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	}
+}
+
+// customizeDiffAvatarHash marks the `avatar_hash` as changed if an avatar is given without a hash,
+// because a changed image in the same file can't be detected otherwise.
+func customizeDiffAvatarHash(ctx context.Context, rd *schema.ResourceDiff, meta interface{}) error {
+	if _, ok := rd.GetOk("avatar"); ok {
+		if v, ok := rd.GetOk("avatar_hash"); !ok || v.(string) == "" {
+			if err := rd.SetNewComputed("avatar_hash"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// avatarChanged checks if the avatar has to be uploaded or removed with updateAvatar.
+// Without an `avatar_hash` the avatar is uploaded on every create and update, see customizeDiffAvatarHash.
+func avatarChanged(d *schema.ResourceData) bool {
+	if d.HasChanges("avatar", "avatar_hash") {
+		return true
+	}
+	return d.Get("avatar").(string) != "" && d.Get("avatar_hash").(string) == ""
+}
+
+// updateAvatar uploads the configured avatar of the object with the given API path, e.g. `projects/42`,
+// or removes the avatar of the object if none is configured.
+func updateAvatar(ctx context.Context, client *gitlab.Client, d *schema.ResourceData, path string) error {
+	avatarPath := d.Get("avatar").(string)
+	if avatarPath == "" {
+		log.Printf("[DEBUG] remove avatar of %s", path)
+		req, err := client.NewRequest(http.MethodPut, path, map[string]string{"avatar": ""}, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+		if err != nil {
+			return err
+		}
+		if _, err := client.Do(req, nil); err != nil {
+			return fmt.Errorf("Failed to remove avatar of %s: %w", path, err)
+		}
+		// terraform doesn't care to remove this from state, thus, we do.
+		d.Set("avatar_hash", "")
+		return nil
+	}
+
+	avatarFile, err := os.Open(avatarPath)
+	if err != nil {
+		return fmt.Errorf("Unable to open avatar file %s: %s", avatarPath, err)
+	}
+	defer avatarFile.Close()
+
+	log.Printf("[DEBUG] upload avatar %s for %s", avatarPath, path)
+	req, err := client.UploadRequest(http.MethodPut, path, avatarFile, filepath.Base(avatarPath), gitlab.UploadAvatar, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		return fmt.Errorf("Failed to upload avatar %s for %s: %w", avatarPath, path, err)
+	}
+	return nil
+}
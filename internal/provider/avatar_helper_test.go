@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestGitlab_customizeDiffAvatarHash(t *testing.T) {
+	meta := testFakeGitlabMeta(t, newFakeGitlab(t))
+	resources := New("dev")().ResourcesMap
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		AvatarHash   string
+		WantComputed bool
+	}{
+		"without hash": {AvatarHash: "", WantComputed: true},
+		"with hash":    {AvatarHash: "8d29d9c393facb9d86314eb347a03fde503f2c0422bf55af7df086deb126107e", WantComputed: false},
+	}
+
+	for _, resourceType := range []string{"gitlab_group", "gitlab_user"} {
+		r := resources[resourceType]
+		for name, tc := range cases {
+			config := map[string]interface{}{"avatar": "testdata/gitlab_topic/avatar.png"}
+			if tc.AvatarHash != "" {
+				config["avatar_hash"] = tc.AvatarHash
+			}
+			state := &terraform.InstanceState{ID: "42", Attributes: map[string]string{"avatar": "testdata/gitlab_topic/avatar.png", "avatar_hash": tc.AvatarHash}}
+			diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+			if err != nil {
+				t.Fatalf("%s %s: failed to diff: %v", resourceType, name, err)
+			}
+			avatarHash := diff.Attributes["avatar_hash"]
+			if computed := avatarHash != nil && avatarHash.NewComputed; computed != tc.WantComputed {
+				t.Fatalf("%s %s: got avatar_hash computed %t expected %t", resourceType, name, computed, tc.WantComputed)
+			}
+		}
+	}
+}
+
+func TestGitlab_updateAvatar(t *testing.T) {
+	f := newFakeGitlab(t)
+	meta := testFakeGitlabMeta(t, f)
+	r := New("dev")().ResourcesMap["gitlab_group"]
+	ctx := context.Background()
+
+	config := map[string]interface{}{
+		"name":        "foo",
+		"path":        "foo",
+		"avatar":      "testdata/gitlab_topic/avatar.png",
+		"avatar_hash": "8d29d9c393facb9d86314eb347a03fde503f2c0422bf55af7df086deb126107e",
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, config)
+	if diags := r.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("failed to create group: %v", diags)
+	}
+	if got := d.Get("avatar_url").(string); !strings.HasSuffix(got, "/avatar.png") {
+		t.Fatalf("got avatar_url %q expected the URL of the uploaded avatar.png", got)
+	}
+
+	// The avatar is replaced and then removed.
+	state := d.State()
+	for _, avatar := range []string{"avatar-update.png", ""} {
+		if avatar != "" {
+			config["avatar"] = "testdata/gitlab_topic/" + avatar
+			config["avatar_hash"] = "a58bd926fd3baabd41c56e810f62ade8705d18a4e280fb35764edb4b778444db"
+		} else {
+			delete(config, "avatar")
+			delete(config, "avatar_hash")
+		}
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		if err != nil {
+			t.Fatalf("failed to diff: %v", err)
+		}
+		var diags diag.Diagnostics
+		if state, diags = r.Apply(ctx, state, diff, meta); diags.HasError() {
+			t.Fatalf("failed to update the avatar to %q: %v", avatar, diags)
+		}
+		if got := state.Attributes["avatar_url"]; (avatar == "" && got != "") || (avatar != "" && !strings.HasSuffix(got, "/"+avatar)) {
+			t.Fatalf("got avatar_url %q after updating the avatar to %q", got, avatar)
+		}
+	}
+	if got := state.Attributes["avatar_hash"]; got != "" {
+		t.Fatalf("expected the avatar_hash to be removed, got %q", got)
+	}
+}
+
+func TestGitlab_updateUserAvatar(t *testing.T) {
+	f := newFakeGitlab(t)
+	meta := testFakeGitlabMeta(t, f)
+	r := New("dev")().ResourcesMap["gitlab_user"]
+	ctx := context.Background()
+
+	id := f.addUser("jane", false)
+	state, diags := r.RefreshWithoutUpgrade(ctx, &terraform.InstanceState{ID: fmt.Sprint(id)}, meta)
+	if diags.HasError() {
+		t.Fatalf("failed to read user: %v", diags)
+	}
+	// The attribute is only known after a create, which the fake doesn't support.
+	state.Attributes["skip_confirmation"] = "true"
+	config := map[string]interface{}{"avatar": "testdata/gitlab_topic/avatar.png"}
+	for _, k := range []string{"name", "username", "email", "state"} {
+		config[k] = state.Attributes[k]
+	}
+
+	// Without an avatar_hash the avatar is uploaded by every apply.
+	for i := 0; i < 2; i++ {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		if err != nil {
+			t.Fatalf("failed to diff: %v", err)
+		}
+		f.update(fmt.Sprintf("users/%d", id), map[string]interface{}{"avatar_url": nil})
+		if state, diags = r.Apply(ctx, state, diff, meta); diags.HasError() {
+			t.Fatalf("failed to upload the avatar: %v", diags)
+		}
+		if got := state.Attributes["avatar_url"]; !strings.HasSuffix(got, "/avatar.png") {
+			t.Fatalf("got avatar_url %q expected the URL of the uploaded avatar.png", got)
+		}
+	}
+
+	// The avatar of a user can't be removed, which is reported with a warning.
+	delete(config, "avatar")
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+	state, diags = r.Apply(ctx, state, diff, meta)
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Summary, "The avatar of user jane is kept") {
+		t.Fatalf("expected a warning that the avatar is kept, got %v", diags)
+	}
+	if got := state.Attributes["avatar_url"]; !strings.HasSuffix(got, "/avatar.png") {
+		t.Fatalf("got avatar_url %q expected the kept avatar.png", got)
+	}
+	if got := state.Attributes["avatar_hash"]; got != "" {
+		t.Fatalf("expected the avatar_hash to be removed, got %q", got)
+	}
+}
@@ -4,65 +4,139 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
+	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/xanzy/go-gitlab"
+	"golang.org/x/oauth2"
 )
 
 // Config is per-provider, specifies where to connect to gitlab
 type Config struct {
 	Token         string
+	TokenFile     string
+	TokenCommand  []string
 	BaseURL       string
 	Insecure      bool
 	CACertFile    string
+	CACertPEM     string
 	ClientCert    string
+	ClientCertPEM string
 	ClientKey     string
+	ClientKeyPEM  string
 	EarlyAuthFail bool
+	MaxRetries    int
+	RetryWaitMin  time.Duration
+	RetryWaitMax  time.Duration
+
+	CACertUseSystemPool bool
+
+	RequestsPerSecond     float64
+	MaxConcurrentRequests int
+
+	OAuthClientID     string
+	OAuthClientSecret string
+	OAuthRefreshToken string
+
+	ProxyURL     string
+	NoProxy      string
+	ProxyHeaders map[string]string
+	Headers      map[string]string
+
+	ReadOnly        bool
+	RequestCacheTTL time.Duration
+
+	ReadAfterWriteTimeout time.Duration
+
+	AuditLogPath string
 }
 
+// wrapBaseTransport wraps the transport which sends the requests of all clients to GitLab, if set.
+// It's only set by the tests, e.g. to record and replay the interactions with GitLab, see newCassetteTransport,
+// thus it can't be enabled in a released provider.
+var wrapBaseTransport func(http.RoundTripper) (http.RoundTripper, error)
+
 // Client returns a *gitlab.Client to interact with the configured gitlab instance
 func (c *Config) Client(ctx context.Context) (*gitlab.Client, error) {
-	// Configure TLS/SSL
-	tlsConfig := &tls.Config{}
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	// If a CACertFile has been specified, use that for cert validation
-	if c.CACertFile != "" {
-		caCert, err := os.ReadFile(c.CACertFile)
-		if err != nil {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = tlsConfig
+	t.MaxIdleConnsPerHost = 100
+
+	proxy, err := proxyFunc(c.ProxyURL, c.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+	t.Proxy = proxy
+	if len(c.ProxyHeaders) > 0 {
+		t.ProxyConnectHeader = make(http.Header)
+		for name, value := range c.ProxyHeaders {
+			t.ProxyConnectHeader.Set(name, value)
+		}
+	}
+
+	var inner http.RoundTripper = t
+	if wrapBaseTransport != nil {
+		if inner, err = wrapBaseTransport(t); err != nil {
 			return nil, err
 		}
+	}
 
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
-		tlsConfig.RootCAs = caCertPool
+	// The custom headers are sent with every request to GitLab, including the OAuth2 token requests.
+	base := newHeaderTransport(logging.NewTransport("GitLab", inner), c.Headers)
+
+	// Retries are handled by our own transport, so that every attempt is logged and the retry behavior is configurable.
+	// The client-side rate limit is applied to every attempt, but a request waiting for its retry doesn't occupy a slot.
+	transport := newRateLimitTransport(base, c.RequestsPerSecond, c.MaxConcurrentRequests)
+
+	// Cached responses skip the rate limit. The cache is wrapped by the token transport, so that
+	// the responses are cached per resolved token, e.g. a rotated token of a `token_file` doesn't get the cached
+	// responses of the previous one. The retries of a request are served from the cache, if possible.
+	transport = newCacheTransport(transport, c.RequestCacheTTL)
+
+	tokenSource, err := c.tokenSource(&http.Client{Transport: base})
+	if err != nil {
+		return nil, err
+	}
+	if tokenSource != nil {
+		// The token is retrieved for every attempt, so that a retry picks up a refreshed token.
+		transport = newTokenTransport(transport, tokenSource)
 	}
 
-	// If configured as insecure, turn off SSL verification
-	if c.Insecure {
-		tlsConfig.InsecureSkipVerify = true
+	// Every attempt of a mutating request is audited, including the ones which are retried.
+	if transport, err = newAuditLogTransport(transport, c.AuditLogPath); err != nil {
+		return nil, err
 	}
 
-	// add client cert and key to connection
-	if c.ClientCert != "" && c.ClientKey != "" {
-		clientPair, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
-		if err != nil {
-			return nil, err
-		}
-		tlsConfig.Certificates = []tls.Certificate{clientPair}
+	transport = newRetryTransport(transport, c.MaxRetries, c.RetryWaitMin, c.RetryWaitMax)
+
+	// A read of an object which has just been written is retried as a whole, i.e. including the retries of its attempts.
+	transport = newReadAfterWriteTransport(transport, c.ReadAfterWriteTimeout)
+
+	// Mutating requests are rejected before they are retried or rate limited.
+	if c.ReadOnly {
+		transport = newReadOnlyTransport(transport)
 	}
 
-	t := http.DefaultTransport.(*http.Transport).Clone()
-	t.TLSClientConfig = tlsConfig
-	t.MaxIdleConnsPerHost = 100
+	// The final error responses are recorded for the diagnostics of the resources, see withAPIErrorDiagnostics.
+	transport = newAPIErrorTransport(transport)
 
 	opts := []gitlab.ClientOptionFunc{
 		gitlab.WithHTTPClient(
 			&http.Client{
-				Transport: logging.NewTransport("GitLab", t),
+				Transport: transport,
 			},
 		),
+		gitlab.WithoutRetries(),
 	}
 
 	if c.BaseURL != "" {
@@ -84,3 +158,116 @@ func (c *Config) Client(ctx context.Context) (*gitlab.Client, error) {
 
 	return client, err
 }
+
+// tokenSource returns the source of the token when it's not statically configured.
+// The returned source is nil if the static token should be used.
+func (c *Config) tokenSource(httpClient *http.Client) (oauth2.TokenSource, error) {
+	switch {
+	case c.TokenFile != "":
+		return &tokenFileSource{path: c.TokenFile}, nil
+	case len(c.TokenCommand) > 0:
+		return oauth2.ReuseTokenSource(nil, &tokenCommandSource{command: c.TokenCommand}), nil
+	case c.OAuthRefreshToken != "":
+		return newOAuthRefreshTokenSource(c.BaseURL, httpClient, c.OAuthClientID, c.OAuthClientSecret, c.OAuthRefreshToken)
+	}
+	return nil, nil
+}
+
+// tlsConfig returns the TLS configuration for the connection to the GitLab instance.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	// If a custom CA cert has been specified, use that for cert validation
+	caCert := []byte(c.CACertPEM)
+	if c.CACertFile != "" {
+		var err error
+		if caCert, err = os.ReadFile(c.CACertFile); err != nil {
+			return nil, err
+		}
+	}
+	if len(caCert) > 0 {
+		caCerts, err := parseCertificatesPEM(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+
+		caCertPool := x509.NewCertPool()
+		if c.CACertUseSystemPool {
+			if caCertPool, err = x509.SystemCertPool(); err != nil {
+				return nil, fmt.Errorf("failed to load system certificate pool: %w", err)
+			}
+		}
+		for _, cert := range caCerts {
+			caCertPool.AddCert(cert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	// If configured as insecure, turn off SSL verification
+	if c.Insecure {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	// add client cert and key to connection
+	clientCert := []byte(c.ClientCertPEM)
+	if c.ClientCert != "" {
+		var err error
+		if clientCert, err = os.ReadFile(c.ClientCert); err != nil {
+			return nil, err
+		}
+	}
+	clientKey := []byte(c.ClientKeyPEM)
+	if c.ClientKey != "" {
+		var err error
+		if clientKey, err = os.ReadFile(c.ClientKey); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case len(clientCert) > 0 && len(clientKey) > 0:
+		if _, err := parseCertificatesPEM(clientCert); err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		clientPair, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate and key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientPair}
+	case len(clientCert) > 0:
+		return nil, fmt.Errorf("a client key is required when a client certificate is configured")
+	case len(clientKey) > 0:
+		return nil, fmt.Errorf("a client certificate is required when a client key is configured")
+	}
+
+	return tlsConfig, nil
+}
+
+// parseCertificatesPEM parses all certificates of a PEM encoded certificate chain.
+// In contrast to `x509.CertPool.AppendCertsFromPEM` it reports which part of the chain is malformed.
+func parseCertificatesPEM(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for i := 1; ; i++ {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("PEM block %d is of type %q, expected \"CERTIFICATE\"", i, block.Type)
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate in PEM block %d: %w", i, err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	if rest := strings.TrimSpace(string(data)); rest != "" {
+		return nil, fmt.Errorf("unexpected data after PEM block %d", len(certs))
+	}
+	return certs, nil
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificatePEM generates a self-signed certificate and returns the PEM encoded certificate and key.
+func testCertificatePEM(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gitlab.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+func TestGitlab_parseCertificatesPEM(t *testing.T) {
+	certPEM, keyPEM := testCertificatePEM(t)
+
+	certs, err := parseCertificatesPEM([]byte(certPEM + certPEM))
+	if err != nil {
+		t.Fatalf("expected valid certificate chain, got error: %v", err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("got %d certificates expected 2", len(certs))
+	}
+
+	cases := []struct {
+		PEM   string
+		Error string
+	}{
+		{
+			PEM:   "",
+			Error: "no PEM encoded certificate found",
+		},
+		{
+			PEM:   keyPEM,
+			Error: "PEM block 1 is of type \"EC PRIVATE KEY\"",
+		},
+		{
+			PEM:   certPEM + certPEM[:len(certPEM)/2],
+			Error: "unexpected data after PEM block 1",
+		},
+		{
+			PEM:   "-----BEGIN CERTIFICATE-----\nZm9v\n-----END CERTIFICATE-----\n",
+			Error: "failed to parse certificate in PEM block 1",
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := parseCertificatesPEM([]byte(tc.PEM))
+		if err == nil || !strings.Contains(err.Error(), tc.Error) {
+			t.Fatalf("got error %v expected it to contain %q", err, tc.Error)
+		}
+	}
+}
+
+func TestGitlab_configTLSConfig_inlinePEM(t *testing.T) {
+	certPEM, keyPEM := testCertificatePEM(t)
+
+	config := Config{
+		CACertPEM:           certPEM,
+		CACertUseSystemPool: true,
+		ClientCertPEM:       certPEM,
+		ClientKeyPEM:        keyPEM,
+	}
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Fatalf("expected custom root CAs to be configured")
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("got %d client certificates expected 1", len(tlsConfig.Certificates))
+	}
+
+	config = Config{ClientCertPEM: certPEM}
+	if _, err := config.tlsConfig(); err == nil {
+		t.Fatalf("expected error for client certificate without key")
+	}
+}
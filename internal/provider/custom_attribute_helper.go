@@ -29,7 +29,7 @@ func CreateCustomAttributeResource(idName string, createGetter CreateGetter, cre
 	}
 
 	readFunc := func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		client := meta.(*gitlab.Client)
+		client := meta.(*providerMeta).client
 		getter := createGetter(client)
 		log.Printf("[DEBUG] read Custom Attribute %s", d.Id())
 
@@ -39,6 +39,9 @@ func CreateCustomAttributeResource(idName string, createGetter CreateGetter, cre
 		}
 
 		customAttribute, _, err := getter(id, key)
+		if removeGoneFromState(ctx, d, customAttribute, err) {
+			return nil
+		}
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -48,7 +51,7 @@ func CreateCustomAttributeResource(idName string, createGetter CreateGetter, cre
 	}
 
 	setFunc := func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		client := meta.(*gitlab.Client)
+		client := meta.(*providerMeta).client
 		setter := createSetter(client)
 
 		id := d.Get(idName).(int)
@@ -69,7 +72,7 @@ func CreateCustomAttributeResource(idName string, createGetter CreateGetter, cre
 	}
 
 	deleteFunc := func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		client := meta.(*gitlab.Client)
+		client := meta.(*providerMeta).client
 		deleter := createDeleter(client)
 		log.Printf("[DEBUG] delete Custom Attribute %s", d.Id())
 
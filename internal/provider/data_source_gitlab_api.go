@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var _ = registerDataSource("gitlab_api", func() *schema.Resource {
+	return &schema.Resource{
+		Description: `The ` + "`gitlab_api`" + ` data source allows to send a ` + "`GET`" + ` request to an arbitrary path of the GitLab REST API and retrieve the response.
+
+It's meant to read data which isn't exposed by any other data source yet. The request is sent with the authentication and settings of the provider.
+
+**Upstream API**: [GitLab REST API docs](https://docs.gitlab.com/ee/api/api_resources.html)`,
+
+		ReadContext: dataSourceGitlabAPIRead,
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Description:  "The path of the API endpoint relative to the `base_url` of the provider, e.g. `projects/foo%2Fbar/repository/submodules`. Path parameters must be URL encoded.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"query": {
+				Description: "The query parameters of the request, e.g. `{ per_page = 100 }`.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"response_body": {
+				Description: "The body of the response as a JSON string. Use `jsondecode` to access its fields.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"response_headers": {
+				Description: "The headers of the response, e.g. `X-Total` or `X-Next-Page`. Multiple values of a header are joined by a comma.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+})
+
+func dataSourceGitlabAPIRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	path := d.Get("path").(string)
+	query := url.Values{}
+	for k, v := range d.Get("query").(map[string]interface{}) {
+		query.Set(k, v.(string))
+	}
+
+	log.Printf("[DEBUG] read GitLab API path %s with query %q", path, query.Encode())
+	body, resp, err := sendAPIRequest(ctx, client, "GET", path, query, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s?%s", path, query.Encode()))
+	d.Set("response_body", string(body))
+	if err := d.Set("response_headers", flattenAPIResponseHeaders(resp)); err != nil {
+		return diag.Errorf("failed to set response_headers to state: %v", err)
+	}
+	return nil
+}
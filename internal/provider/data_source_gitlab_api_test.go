@@ -0,0 +1,51 @@
+//go:build acceptance
+// +build acceptance
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceGitlabAPI_basic(t *testing.T) {
+	testProject := testAccCreateProject(t)
+	testAccCreateProjectIssues(t, testProject.ID, 3)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "gitlab_api" "this" {
+						path  = "projects/%d/issues"
+						query = {
+							per_page = 2
+							sort     = "asc"
+						}
+					}
+
+					output "iids" {
+						value = join(",", jsondecode(data.gitlab_api.this.response_body)[*].iid)
+					}
+				`, testProject.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("iids", "1,2"),
+					resource.TestCheckResourceAttr("data.gitlab_api.this", "response_headers.X-Total", "3"),
+					resource.TestCheckResourceAttr("data.gitlab_api.this", "response_headers.X-Next-Page", "2"),
+				),
+			},
+			{
+				Config: `
+					data "gitlab_api" "this" {
+						path = "projects/0"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`404 Project Not Found`),
+			},
+		},
+	})
+}
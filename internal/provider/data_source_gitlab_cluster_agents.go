@@ -38,7 +38,7 @@ var _ = registerDataSource("gitlab_cluster_agents", func() *schema.Resource {
 })
 
 func dataSourceGitlabClusterAgentsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	options := gitlab.ListAgentsOptions{
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/mitchellh/hashstructure"
+)
+
+var _ = registerDataSource("gitlab_graphql_query", func() *schema.Resource {
+	return &schema.Resource{
+		Description: `The ` + "`gitlab_graphql_query`" + ` data source allows to send an arbitrary query to the GitLab GraphQL API and retrieve its result.
+
+It's meant to read data which is only available in the GraphQL API, e.g. work items, iterations or compliance frameworks,
+or which isn't exposed by any other data source yet. Mutations are not supported.
+
+**Upstream API**: [GitLab GraphQL API docs](https://docs.gitlab.com/ee/api/graphql/reference/)`,
+
+		ReadContext: dataSourceGitlabGraphQLQueryRead,
+		Schema: map[string]*schema.Schema{
+			"query": {
+				Description: "The GraphQL query, e.g. `query($fullPath: ID!) { project(fullPath: $fullPath) { id } }`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+					if !isGraphQLQueryDocument(i.(string)) {
+						return nil, []error{fmt.Errorf("%s must not contain a mutation or subscription", k)}
+					}
+					return nil, nil
+				},
+			},
+			"variables": {
+				Description:   "The values of the variables declared by the query, passed as strings. Use `variables_json` for variables of other types, e.g. `Int` or lists.",
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"variables_json"},
+			},
+			"variables_json": {
+				Description:   "The values of the variables declared by the query as a JSON object, e.g. `jsonencode({ first = 10 })`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringIsJSON,
+				ConflictsWith: []string{"variables"},
+			},
+			"paginate": {
+				Description: "The dot separated path of a connection in the query result to follow the pagination of, e.g. `project.workItems`. The query must request the `pageInfo { hasNextPage endCursor }` of the connection and pass an `$after` variable as its `after` argument. The `nodes` and `edges` of all pages are merged into the result.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"data": {
+				Description: "The `data` of the GraphQL response as a JSON string. Use `jsondecode` to access its fields.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+})
+
+func dataSourceGitlabGraphQLQueryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	query := GraphQLQuery{
+		Query:     d.Get("query").(string),
+		Variables: map[string]interface{}{},
+	}
+	for k, v := range d.Get("variables").(map[string]interface{}) {
+		query.Variables[k] = v
+	}
+	if v, ok := d.GetOk("variables_json"); ok {
+		if err := json.Unmarshal([]byte(v.(string)), &query.Variables); err != nil {
+			return diag.Errorf("variables_json must be a JSON object: %v", err)
+		}
+	}
+
+	queryHash, err := hashstructure.Hash(query, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] executing GraphQL Query %s", query.Query)
+
+	var data interface{}
+	if connectionPath, ok := d.GetOk("paginate"); ok {
+		err = SendPaginatedGraphQLRequest(ctx, client, query, connectionPath.(string), func(page json.RawMessage) error {
+			var pageData interface{}
+			if err := json.Unmarshal(page, &pageData); err != nil {
+				return err
+			}
+			if data == nil {
+				data = pageData
+				return nil
+			}
+			return mergeGraphQLConnectionPage(data, pageData, connectionPath.(string))
+		})
+	} else {
+		var response struct {
+			Data interface{} `json:"data"`
+		}
+		_, err = SendGraphQLRequest(ctx, client, query, &response)
+		data = response.Data
+	}
+	if err != nil {
+		return diagnosticsFromGraphQLError(err)
+	}
+
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", queryHash))
+	d.Set("data", string(dataJSON))
+	return nil
+}
+
+// mergeGraphQLConnectionPage appends the `nodes` and `edges` of the connection at connectionPath in page to the ones in data
+// and replaces its `pageInfo`.
+func mergeGraphQLConnectionPage(data, page interface{}, connectionPath string) error {
+	connection := func(v interface{}) (map[string]interface{}, error) {
+		for _, field := range strings.Split(connectionPath, ".") {
+			object, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("GraphQL response has no object at %q", connectionPath)
+			}
+			v = object[field]
+		}
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("GraphQL response has no connection at %q", connectionPath)
+		}
+		return object, nil
+	}
+
+	dataConnection, err := connection(data)
+	if err != nil {
+		return err
+	}
+	pageConnection, err := connection(page)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range []string{"nodes", "edges"} {
+		if items, ok := pageConnection[field].([]interface{}); ok {
+			existing, _ := dataConnection[field].([]interface{})
+			dataConnection[field] = append(existing, items...)
+		}
+	}
+	dataConnection["pageInfo"] = pageConnection["pageInfo"]
+	return nil
+}
@@ -0,0 +1,76 @@
+//go:build acceptance
+// +build acceptance
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceGitlabGraphQLQuery_basic(t *testing.T) {
+	testProject := testAccCreateProject(t)
+	testAccCreateProjectIssues(t, testProject.ID, 3)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					data "gitlab_graphql_query" "this" {
+						query     = "query($fullPath: ID!) { project(fullPath: $fullPath) { fullPath } }"
+						variables = {
+							fullPath = "%s"
+						}
+					}
+				`, testProject.PathWithNamespace),
+				Check: resource.TestCheckResourceAttr("data.gitlab_graphql_query.this", "data", fmt.Sprintf(`{"project":{"fullPath":"%s"}}`, testProject.PathWithNamespace)),
+			},
+			{
+				Config: fmt.Sprintf(`
+					data "gitlab_graphql_query" "this" {
+						query = <<-EOT
+							query($fullPath: ID!, $first: Int, $after: String) {
+								project(fullPath: $fullPath) {
+									issues(first: $first, after: $after, sort: CREATED_ASC) {
+										nodes { iid }
+										pageInfo { hasNextPage endCursor }
+									}
+								}
+							}
+						EOT
+						variables_json = jsonencode({
+							fullPath = "%s"
+							first    = 2
+						})
+						paginate = "project.issues"
+					}
+
+					output "iids" {
+						value = join(",", jsondecode(data.gitlab_graphql_query.this.data).project.issues.nodes[*].iid)
+					}
+				`, testProject.PathWithNamespace),
+				Check: resource.TestCheckOutput("iids", "1,2,3"),
+			},
+			{
+				Config: `
+					data "gitlab_graphql_query" "this" {
+						query = "query { project(fullPath: 1) { unknownField } }"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`GraphQL request failed`),
+			},
+			{
+				Config: `
+					data "gitlab_graphql_query" "this" {
+						query = "mutation { echoCreate(input: {}) { errors } }"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`must not contain a mutation or subscription`),
+			},
+		},
+	})
+}
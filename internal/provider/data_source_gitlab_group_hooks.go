@@ -34,7 +34,7 @@ var _ = registerDataSource("gitlab_group_hooks", func() *schema.Resource {
 })
 
 func dataSourceGitlabGroupHooksRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	group := d.Get("group").(string)
 	options := gitlab.ListGroupHooksOptions{
@@ -42,7 +42,7 @@ var _ = registerDataSource("gitlab_group_variables", func() *schema.Resource {
 })
 
 func dataSourceGitlabGroupVariablesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group := d.Get("group").(string)
 	environmentScope := d.Get("environment_scope").(string)
 
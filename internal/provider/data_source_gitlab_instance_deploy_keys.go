@@ -109,7 +109,7 @@ var _ = registerDataSource("gitlab_instance_deploy_keys", func() *schema.Resourc
 })
 
 func dataSourceGitlabInstanceDeployKeysRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	// Get group memberships
 	options := &gitlab.ListInstanceDeployKeysOptions{
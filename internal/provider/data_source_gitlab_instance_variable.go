@@ -20,7 +20,7 @@ var _ = registerDataSource("gitlab_instance_variable", func() *schema.Resource {
 })
 
 func dataSourceGitlabInstanceVariableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	key := d.Get("key").(string)
 
 	variable, _, err := client.InstanceVariables.GetVariable(key, nil, gitlab.WithContext(ctx))
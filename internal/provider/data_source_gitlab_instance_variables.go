@@ -29,7 +29,7 @@ var _ = registerDataSource("gitlab_instance_variables", func() *schema.Resource
 })
 
 func dataSourceGitlabInstanceVariablesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	options := &gitlab.ListInstanceVariablesOptions{
 		Page:    1,
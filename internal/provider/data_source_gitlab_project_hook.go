@@ -21,7 +21,7 @@ var _ = registerDataSource("gitlab_project_hook", func() *schema.Resource {
 })
 
 func dataSourceGitlabProjectHookRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	hookID := d.Get("hook_id").(int)
 
@@ -34,7 +34,7 @@ var _ = registerDataSource("gitlab_project_hooks", func() *schema.Resource {
 })
 
 func dataSourceGitlabProjectHooksRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	options := gitlab.ListProjectHooksOptions{
@@ -99,7 +99,7 @@ var _ = registerDataSource("gitlab_project_membership", func() *schema.Resource
 })
 
 func dataSourceGitlabProjectMembershipRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	var project *gitlab.Project
 	var err error
@@ -20,7 +20,7 @@ var _ = registerDataSource("gitlab_project_milestone", func() *schema.Resource {
 })
 
 func dataSourceGitlabProjectMilestoneRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	milestoneID := d.Get("milestone_id").(int)
 
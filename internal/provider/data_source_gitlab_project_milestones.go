@@ -68,7 +68,7 @@ var _ = registerDataSource("gitlab_project_milestones", func() *schema.Resource
 })
 
 func dataSourceGitlabProjectMilestonesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	options := gitlab.ListMilestonesOptions{
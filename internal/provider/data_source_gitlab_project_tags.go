@@ -52,7 +52,7 @@ var _ = registerDataSource("gitlab_project_tags", func() *schema.Resource {
 })
 
 func dataSourceGitlabProjectTagsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	options := gitlab.ListTagsOptions{
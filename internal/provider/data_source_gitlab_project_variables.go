@@ -42,7 +42,7 @@ var _ = registerDataSource("gitlab_project_variables", func() *schema.Resource {
 })
 
 func dataSourceGitlabProjectVariablesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	environmentScope := d.Get("environment_scope").(string)
 
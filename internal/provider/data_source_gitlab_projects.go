@@ -961,7 +961,7 @@ var _ = registerDataSource("gitlab_projects", func() *schema.Resource {
 // CRUD methods
 
 func dataSourceGitlabProjectsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	var projectList []*gitlab.Project
 
 	// Permanent parameters
@@ -43,7 +43,7 @@ var _ = registerDataSource("gitlab_release_links", func() *schema.Resource {
 })
 
 func dataSourceGitlabReleaseLinksRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	tagName := d.Get("tag_name").(string)
@@ -77,7 +77,7 @@ var _ = registerDataSource("gitlab_repository_tree", func() *schema.Resource {
 })
 
 func dataSourceGitlabRepositoryTreeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	options := &gitlab.ListTreeOptions{
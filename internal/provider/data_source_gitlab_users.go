@@ -226,7 +226,7 @@ var _ = registerDataSource("gitlab_users", func() *schema.Resource {
 })
 
 func dataSourceGitlabUsersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	listUsersOptions, id, err := expandGitlabUsersOptions(d)
 	if err != nil {
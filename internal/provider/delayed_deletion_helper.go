@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// permanentlyRemoveOnDestroySchema returns the `permanently_remove_on_destroy` attribute for resources of the given kind of object,
+// which must remove the object with permanentlyRemove in their delete function if it has been marked for deletion.
+func permanentlyRemoveOnDestroySchema(object string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("Set to `true` to immediately remove the %[1]s on destroy, if the GitLab instance has delayed deletion enabled. "+
+			"Otherwise, the %[1]s is only marked for deletion, and it keeps its path until it's removed after the configured delay. "+
+			"Requires a GitLab version which supports the `permanently_remove` parameter of the delete API.", object),
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+}
+
+// markedForDeletionSchema returns the computed attribute which contains the date on which the given kind of object
+// has been marked for deletion. The resources must plan the restore of such objects with customizeDiffRestoreMarkedForDeletion.
+func markedForDeletionSchema(object string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("The date on which the %[1]s has been marked for deletion, if the GitLab instance has delayed deletion enabled. "+
+			"A %[1]s which is marked for deletion is restored on the next apply, if it's still in the configuration.", object),
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+}
+
+// customizeDiffRestoreMarkedForDeletion returns a CustomizeDiff function which plans an update,
+// if the given attribute of the state says that the object has been marked for deletion.
+// The update must restore the object, if the old value of the attribute is not empty.
+func customizeDiffRestoreMarkedForDeletion(attribute string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, rd *schema.ResourceDiff, meta interface{}) error {
+		if rd.Id() == "" || rd.Get(attribute).(string) == "" {
+			return nil
+		}
+		return rd.SetNew(attribute, "")
+	}
+}
+
+// permanentlyRemoveOptions are the options of the second delete request for objects which are marked for deletion.
+type permanentlyRemoveOptions struct {
+	PermanentlyRemove *bool   `url:"permanently_remove,omitempty" json:"permanently_remove,omitempty"`
+	FullPath          *string `url:"full_path,omitempty" json:"full_path,omitempty"`
+}
+
+// permanentlyRemove removes the object with the given API path, e.g. `projects/42`, which is marked for deletion,
+// right away instead of after the deletion delay. The API requires the full path of the object as confirmation.
+func permanentlyRemove(ctx context.Context, client *gitlab.Client, path string, fullPath string) error {
+	log.Printf("[DEBUG] permanently remove %s (%s)", path, fullPath)
+
+	options := &permanentlyRemoveOptions{
+		PermanentlyRemove: gitlab.Bool(true),
+		FullPath:          gitlab.String(fullPath),
+	}
+	req, err := client.NewRequest(http.MethodDelete, path, options, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		return fmt.Errorf("failed to permanently remove %s: %w", fullPath, err)
+	}
+	return nil
+}
+
+// restoreProject restores the given project, which is marked for deletion.
+// go-gitlab doesn't support the restore API of projects yet, unlike the one of groups.
+func restoreProject(ctx context.Context, client *gitlab.Client, pid interface{}) error {
+	req, err := client.NewRequest(http.MethodPost, fmt.Sprintf("projects/%s/restore", gitlab.PathEscape(fmt.Sprint(pid))), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	_, err = client.Do(req, nil)
+	return err
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// deletionProtectionSchema returns the `deletion_protection` attribute for resources of the given kind of object,
+// which must be checked with checkDeletionProtection in their delete function.
+func deletionProtectionSchema(object string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("Set to `true` to prevent the %[1]s from being destroyed. A destroy of the %[1]s fails with an error, until `deletion_protection` has been set to `false` and applied. Unlike the `prevent_destroy` lifecycle argument, it may be set from variables.", object),
+		Type:        schema.TypeBool,
+		Optional:    true,
+	}
+}
+
+// checkDeletionProtection returns an error diagnostic if the `deletion_protection` of the given resource is enabled.
+// Like `archive_on_destroy` of projects, the value of the state is used, thus it must have been applied before the destroy.
+func checkDeletionProtection(d *schema.ResourceData, resourceType string) diag.Diagnostics {
+	if !d.Get("deletion_protection").(bool) {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("%s %s is protected from deletion", resourceType, d.Id()),
+		Detail: "The resource has `deletion_protection` set to `true`, thus it can't be destroyed or replaced. " +
+			"To destroy it, set `deletion_protection = false` and apply that change first.",
+		AttributePath: cty.GetAttrPath("deletion_protection"),
+	}}
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitlab_checkDeletionProtection(t *testing.T) {
+	f := newFakeGitlab(t)
+	meta := testFakeGitlabMeta(t, f)
+	resources := New("test")().ResourcesMap
+
+	project, _, err := meta.client.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: gitlab.String("foo")})
+	if err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	cases := []struct {
+		ResourceType string
+		ID           string
+	}{
+		{ResourceType: "gitlab_project", ID: "root/foo"},
+		{ResourceType: "gitlab_group", ID: "42"},
+		{ResourceType: "gitlab_user", ID: "42"},
+		{ResourceType: "gitlab_cluster_agent", ID: "root/foo:42"},
+	}
+
+	for _, tc := range cases {
+		r := resources[tc.ResourceType]
+		d := r.TestResourceData()
+		d.SetId(tc.ID)
+		if err := d.Set("deletion_protection", true); err != nil {
+			t.Fatalf("%s: failed to set deletion_protection: %v", tc.ResourceType, err)
+		}
+
+		diags := r.DeleteContext(context.Background(), d, meta)
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.ResourceType+" "+tc.ID+" is protected from deletion") {
+			t.Fatalf("%s: expected the delete to fail because of the deletion protection, got %v", tc.ResourceType, diags)
+		}
+	}
+
+	if _, _, err := meta.client.Projects.GetProject(project.ID, nil); err != nil {
+		t.Fatalf("expected the protected project to still exist: %v", err)
+	}
+}
+
+func TestGitlab_updateDeletionProtection(t *testing.T) {
+	f := newFakeGitlab(t)
+	meta := testFakeGitlabMeta(t, f)
+	r := New("test")().ResourcesMap["gitlab_user"]
+	ctx := context.Background()
+
+	// The fake only supports to upload the avatar of a user, thus the update fails if the user is modified.
+	id := f.addUser("jane", false)
+	state, diags := r.RefreshWithoutUpgrade(ctx, &terraform.InstanceState{ID: fmt.Sprint(id)}, meta)
+	if diags.HasError() {
+		t.Fatalf("failed to read user: %v", diags)
+	}
+	// The attribute is only known after a create, which the fake doesn't support either.
+	state.Attributes["skip_confirmation"] = "true"
+	config := map[string]interface{}{"deletion_protection": true}
+	for _, k := range []string{"name", "username", "email", "state"} {
+		config[k] = state.Attributes[k]
+	}
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+	state, diags = r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("failed to update deletion_protection: %v", diags)
+	}
+	if state.Attributes["deletion_protection"] != "true" {
+		t.Fatalf("expected deletion_protection to be enabled, got %v", state.Attributes)
+	}
+}
@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/xanzy/go-gitlab"
+)
+
+// diagnosticsFromAPIError translates the given error into diagnostics. If it's a `*gitlab.ErrorResponse`,
+// the field errors of a validation error are returned as separate diagnostics pointing at the attribute
+// of the given schema with the same name, and common status codes get a hint how to resolve them.
+// Any other error is returned as is.
+func diagnosticsFromAPIError(err error, s map[string]*schema.Schema) diag.Diagnostics {
+	var errResponse *gitlab.ErrorResponse
+	if !errors.As(err, &errResponse) || errResponse.Response == nil {
+		return diag.FromErr(err)
+	}
+	return apiErrorDiagnostics(errResponse, err.Error(), s)
+}
+
+// apiErrorDiagnostics returns the diagnostics for the given error response.
+// The summary is the message of the error the response has been returned with.
+func apiErrorDiagnostics(errResponse *gitlab.ErrorResponse, summary string, s map[string]*schema.Schema) diag.Diagnostics {
+	if fieldErrors := apiFieldErrors(errResponse.Body); len(fieldErrors) > 0 {
+		fields := make([]string, 0, len(fieldErrors))
+		for field := range fieldErrors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		var diags diag.Diagnostics
+		for _, field := range fields {
+			var path cty.Path
+			if _, ok := s[field]; ok {
+				path = cty.GetAttrPath(field)
+			}
+			for _, message := range fieldErrors[field] {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       fmt.Sprintf("%s %s", field, message),
+					Detail:        summary,
+					AttributePath: path,
+				})
+			}
+		}
+		return diags
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   apiErrorHint(errResponse),
+	}}
+}
+
+// apiFieldErrors parses the field errors of a validation error response,
+// e.g. `{"message": {"name": ["has already been taken"]}}`.
+// Errors which are not specific to a field, like the ones of the `base` field, are not returned.
+func apiFieldErrors(body []byte) map[string][]string {
+	var payload struct {
+		Message map[string]json.RawMessage `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+
+	fieldErrors := make(map[string][]string)
+	for field, raw := range payload.Message {
+		if field == "base" {
+			continue
+		}
+
+		var messages []string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			var message string
+			if err := json.Unmarshal(raw, &message); err != nil {
+				continue
+			}
+			messages = []string{message}
+		}
+		if len(messages) > 0 {
+			fieldErrors[field] = messages
+		}
+	}
+	return fieldErrors
+}
+
+// apiErrorHint returns a hint how to resolve the error of the given response, if its status code is a common one.
+func apiErrorHint(errResponse *gitlab.ErrorResponse) string {
+	switch errResponse.Response.StatusCode {
+	case http.StatusUnauthorized:
+		return "The token is invalid, expired or revoked. Check the token configured for the provider."
+	case http.StatusForbidden:
+		if strings.Contains(errResponse.Message, "insufficient_scope") {
+			return "The token is missing a required scope. Most resources require a token with the `api` scope."
+		}
+		return "The user of the token doesn't have the required role for this operation, e.g. Maintainer or Owner of the project or group. Some operations require an administrator."
+	case http.StatusNotFound:
+		return "The object doesn't exist or isn't visible to the user of the token. " +
+			"Some features are only available with a GitLab Enterprise Edition license and respond with a 404 otherwise."
+	}
+	return ""
+}
+
+type apiErrorsContextKey struct{}
+
+// apiErrorRecorder collects the error responses of the API requests made with a context, see withAPIErrorDiagnostics.
+type apiErrorRecorder struct {
+	mu     sync.Mutex
+	errors []*gitlab.ErrorResponse
+}
+
+// newAPIErrorTransport wraps the given transport so that every error response is recorded
+// by the apiErrorRecorder of the request context, if there is one.
+func newAPIErrorTransport(transport http.RoundTripper) http.RoundTripper {
+	return &apiErrorTransport{transport: transport}
+}
+
+type apiErrorTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *apiErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	recorder, ok := req.Context().Value(apiErrorsContextKey{}).(*apiErrorRecorder)
+	if err != nil || !ok || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// The copy is parsed exactly like the go-gitlab client parses the response, so that the errors are equal.
+	recorded := *resp
+	recorded.Body = io.NopCloser(bytes.NewReader(body))
+	if errResponse, ok := gitlab.CheckResponse(&recorded).(*gitlab.ErrorResponse); ok {
+		recorder.mu.Lock()
+		recorder.errors = append(recorder.errors, errResponse)
+		recorder.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// withAPIErrorDiagnostics wraps the CRUD functions of the given resource or data source, so that the
+// error diagnostics they return for an error response of the GitLab API are translated with apiErrorDiagnostics.
+// This way the resources can keep returning `diag.FromErr(err)`.
+func withAPIErrorDiagnostics(r *schema.Resource) *schema.Resource {
+	wrap := func(fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if fn == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			recorder := &apiErrorRecorder{}
+			diags := fn(context.WithValue(ctx, apiErrorsContextKey{}, recorder), d, meta)
+			if !diags.HasError() || len(recorder.errors) == 0 {
+				return diags
+			}
+
+			var translated diag.Diagnostics
+			for _, diagnostic := range diags {
+				translated = append(translated, translateAPIErrorDiagnostic(diagnostic, recorder.errors, r.Schema)...)
+			}
+			return translated
+		}
+	}
+
+	r.CreateContext = wrap(r.CreateContext)
+	r.ReadContext = wrap(r.ReadContext)
+	r.UpdateContext = wrap(r.UpdateContext)
+	r.DeleteContext = wrap(r.DeleteContext)
+	return r
+}
+
+// translateAPIErrorDiagnostic translates the given diagnostic if it's the plain error of one of the given error responses.
+func translateAPIErrorDiagnostic(d diag.Diagnostic, errResponses []*gitlab.ErrorResponse, s map[string]*schema.Schema) diag.Diagnostics {
+	if d.Severity != diag.Error || d.Detail != "" || len(d.AttributePath) > 0 {
+		return diag.Diagnostics{d}
+	}
+	// The latest response is the most likely cause of the error.
+	for i := len(errResponses) - 1; i >= 0; i-- {
+		if strings.Contains(d.Summary, errResponses[i].Error()) {
+			return apiErrorDiagnostics(errResponses[i], d.Summary, s)
+		}
+	}
+	return diag.Diagnostics{d}
+}
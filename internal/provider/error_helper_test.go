@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitlab_diagnosticsFromAPIError(t *testing.T) {
+	s := map[string]*schema.Schema{"name": {}, "path": {}}
+	errResponse := func(status int, body string) error {
+		req, _ := http.NewRequest(http.MethodPost, "https://gitlab.example.com/api/v4/projects", nil)
+		return &gitlab.ErrorResponse{
+			Body:     []byte(body),
+			Response: &http.Response{StatusCode: status, Request: req},
+			Message:  body,
+		}
+	}
+
+	cases := []struct {
+		Name       string
+		Err        error
+		WantDiags  []string
+		WantPaths  []string
+		WantDetail string
+	}{
+		{
+			Name:      "field errors",
+			Err:       errResponse(400, `{"message": {"path": ["has already been taken", "is reserved"], "name": ["has already been taken"], "base": ["is invalid"], "namespace": "is not valid"}}`),
+			WantDiags: []string{"name has already been taken", "namespace is not valid", "path has already been taken", "path is reserved"},
+			WantPaths: []string{"name", "", "path", "path"},
+		},
+		{
+			Name:      "plain message",
+			Err:       errResponse(400, `{"message": "name is missing"}`),
+			WantDiags: []string{`POST https://gitlab.example.com/api/v4/projects: 400 {"message": "name is missing"}`},
+			WantPaths: []string{""},
+		},
+		{
+			Name:       "unauthorized",
+			Err:        errResponse(401, `{"message": "401 Unauthorized"}`),
+			WantDiags:  []string{`POST https://gitlab.example.com/api/v4/projects: 401 {"message": "401 Unauthorized"}`},
+			WantPaths:  []string{""},
+			WantDetail: "The token is invalid",
+		},
+		{
+			Name:       "insufficient scope",
+			Err:        errResponse(403, `{"error": "insufficient_scope"}`),
+			WantDiags:  []string{`POST https://gitlab.example.com/api/v4/projects: 403 {"error": "insufficient_scope"}`},
+			WantPaths:  []string{""},
+			WantDetail: "missing a required scope",
+		},
+		{
+			Name:       "forbidden",
+			Err:        fmt.Errorf("failed to create project: %w", errResponse(403, `{"message": "403 Forbidden"}`)),
+			WantDiags:  []string{`failed to create project: POST https://gitlab.example.com/api/v4/projects: 403 {"message": "403 Forbidden"}`},
+			WantPaths:  []string{""},
+			WantDetail: "required role",
+		},
+		{
+			Name:       "not found",
+			Err:        errResponse(404, `{"message": "404 Not Found"}`),
+			WantDiags:  []string{`POST https://gitlab.example.com/api/v4/projects: 404 {"message": "404 Not Found"}`},
+			WantPaths:  []string{""},
+			WantDetail: "Enterprise Edition license",
+		},
+		{
+			Name:      "other error",
+			Err:       errors.New("connection refused"),
+			WantDiags: []string{"connection refused"},
+			WantPaths: []string{""},
+		},
+	}
+
+	for _, tc := range cases {
+		diags := diagnosticsFromAPIError(tc.Err, s)
+		if len(diags) != len(tc.WantDiags) {
+			t.Fatalf("%s: got diagnostics %v expected %v", tc.Name, diags, tc.WantDiags)
+		}
+		for i, d := range diags {
+			wantPath := cty.Path(nil)
+			if tc.WantPaths[i] != "" {
+				wantPath = cty.GetAttrPath(tc.WantPaths[i])
+			}
+			if d.Severity != diag.Error || d.Summary != tc.WantDiags[i] || !d.AttributePath.Equals(wantPath) {
+				t.Fatalf("%s: got diagnostic %d %q at %#v expected %q at %q", tc.Name, i, d.Summary, d.AttributePath, tc.WantDiags[i], tc.WantPaths[i])
+			}
+			if !strings.Contains(d.Detail, tc.WantDetail) {
+				t.Fatalf("%s: got detail %q expected it to contain %q", tc.Name, d.Detail, tc.WantDetail)
+			}
+		}
+	}
+}
+
+func TestGitlab_withAPIErrorDiagnostics(t *testing.T) {
+	f := newFakeGitlab(t)
+	meta := testFakeGitlabMeta(t, f)
+	resources := New("dev")().ResourcesMap
+	ctx := context.Background()
+
+	project := resources["gitlab_project"]
+	if diags := project.CreateContext(ctx, schema.TestResourceDataRaw(t, project.Schema, map[string]interface{}{"name": "foo"}), meta); diags.HasError() {
+		t.Fatalf("failed to create project: %v", diags)
+	}
+
+	// The field error of the API is attached to the attribute.
+	diags := project.CreateContext(ctx, schema.TestResourceDataRaw(t, project.Schema, map[string]interface{}{"name": "bar", "path": "foo"}), meta)
+	if len(diags) != 1 || diags[0].Summary != "path has already been taken" || !diags[0].AttributePath.Equals(cty.GetAttrPath("path")) {
+		t.Fatalf("got diagnostics %#v", diags)
+	}
+
+	// Errors without field errors get a hint.
+	variable := resources["gitlab_project_variable"]
+	d := schema.TestResourceDataRaw(t, variable.Schema, map[string]interface{}{"project": "does/not-exist", "key": "FOO", "value": "foo"})
+	diags = variable.CreateContext(ctx, d, meta)
+	if len(diags) != 1 || !strings.Contains(diags[0].Summary, "404 {message: 404 Project Not Found}") || !strings.Contains(diags[0].Detail, "isn't visible to the user of the token") {
+		t.Fatalf("got diagnostics %#v", diags)
+	}
+}
@@ -0,0 +1,1417 @@
+package provider
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// fakeGitlabToken is the only token accepted by the fake GitLab API.
+const fakeGitlabToken = "fake-gitlab-token"
+
+// fakeGitlab is an in-process fake of the core endpoints of the GitLab REST API with in-memory state.
+// It supports users, groups, projects, forks, avatars, members, variables, branches, protected branches and project hooks,
+// including the lists of them, but only their first page,
+// which is enough to test the CRUD, import and drift detection logic of the corresponding resources
+// with `resource.UnitTest` instead of against a live GitLab instance:
+//
+//	fake := newFakeGitlab(t)
+//	resource.UnitTest(t, resource.TestCase{
+//		ProviderFactories: fake.providerFactories(t),
+//		Steps: []resource.TestStep{
+//			{Config: fake.ProviderConfig() + `resource "gitlab_project" "this" { ... }`},
+//		},
+//	})
+//
+// Changes made outside of Terraform can be simulated with update and delete.
+// Responses contain the attributes the provider uses, not every attribute of the real API.
+type fakeGitlab struct {
+	server *httptest.Server
+
+	// Version and Enterprise are returned by the `/version` and `/metadata` endpoints.
+	Version    string
+	Enterprise bool
+	// ImportError makes the imports of projects from an `import_url` fail with it.
+	ImportError string
+	// TransferReads delays the transfers of projects: they respond with the old namespace and the project is
+	// only moved after it has been read as many times.
+	TransferReads int
+
+	mu        sync.Mutex
+	nextID    int
+	objects   map[string]fakeGitlabObject
+	transfers map[string]*fakeGitlabTransfer
+}
+
+// fakeGitlabTransfer is a pending transfer of a project, see fakeGitlab.TransferReads.
+type fakeGitlabTransfer struct {
+	namespaceID int
+	reads       int
+}
+
+type fakeGitlabObject map[string]interface{}
+
+// fakeGitlabError is returned by the handlers to respond with an error status and message.
+type fakeGitlabError struct {
+	status  int
+	message interface{}
+}
+
+func (e *fakeGitlabError) Error() string {
+	return fmt.Sprintf("%d %v", e.status, e.message)
+}
+
+func fakeGitlabNotFound(what string) *fakeGitlabError {
+	return &fakeGitlabError{status: http.StatusNotFound, message: "404 " + what + " Not Found"}
+}
+
+func fakeGitlabBadRequest(message interface{}) *fakeGitlabError {
+	return &fakeGitlabError{status: http.StatusBadRequest, message: message}
+}
+
+type fakeGitlabRoute struct {
+	method  string
+	pattern string
+	handler func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error)
+}
+
+// newFakeGitlab starts a fake GitLab API, which is stopped at the end of the test.
+// The `root` administrator with ID 1 and its personal namespace exist from the start.
+func newFakeGitlab(t *testing.T) *fakeGitlab {
+	f := &fakeGitlab{
+		Version:    "15.5.0-ee",
+		Enterprise: true,
+		nextID:     1,
+		objects:    make(map[string]fakeGitlabObject),
+		transfers:  make(map[string]*fakeGitlabTransfer),
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+	f.addUser("root", true)
+	return f
+}
+
+// BaseURL returns the URL to use as `base_url` of the provider.
+func (f *fakeGitlab) BaseURL() string {
+	return f.server.URL + "/api/v4/"
+}
+
+// ProviderConfig returns a provider block configured for the fake GitLab API.
+func (f *fakeGitlab) ProviderConfig() string {
+	return fmt.Sprintf(`
+provider "gitlab" {
+  base_url = %q
+  token    = %q
+}
+`, f.BaseURL(), fakeGitlabToken)
+}
+
+// providerFactories returns the provider factories to use in `resource.UnitTest`.
+// The tests are skipped if no Terraform CLI is available, because `resource.UnitTest` would try to download one.
+func (f *fakeGitlab) providerFactories(t *testing.T) map[string]func() (*schema.Provider, error) {
+	if _, err := exec.LookPath("terraform"); err != nil && os.Getenv("TF_ACC_TERRAFORM_PATH") == "" {
+		t.Skip("the Terraform CLI is required to run unit tests against the fake GitLab API")
+	}
+	return map[string]func() (*schema.Provider, error){
+		"gitlab": func() (*schema.Provider, error) {
+			return New("dev")(), nil
+		},
+	}
+}
+
+// addUser adds a user to the fake GitLab API and returns its ID.
+func (f *fakeGitlab) addUser(username string, admin bool) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.newID()
+	f.objects[fmt.Sprintf("users/%d", id)] = fakeGitlabObject{
+		"id":       id,
+		"username": username,
+		"name":     username,
+		"state":    "active",
+		"is_admin": admin,
+		"web_url":  f.server.URL + "/" + username,
+	}
+	// Every user has a personal namespace, which is stored like a group.
+	f.objects[fmt.Sprintf("namespaces/%d", id)] = fakeGitlabObject{
+		"id":        id,
+		"name":      username,
+		"path":      username,
+		"full_path": username,
+		"kind":      "user",
+	}
+	return id
+}
+
+// object returns a copy of the object at the given canonical path, e.g. `projects/2` or `projects/2/variables/FOO/*`.
+func (f *fakeGitlab) object(path string) (fakeGitlabObject, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[path]
+	if !ok {
+		return nil, false
+	}
+	return object.copy(), true
+}
+
+// path returns the canonical path, e.g. `projects/2`, of the project or group with the given ID or full path.
+func (f *fakeGitlab) path(kind, id string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path, _, _ := f.resolve(kind, id)
+	return path
+}
+
+// update changes the given attributes of the object at the given canonical path, e.g. to simulate a change made outside of Terraform.
+func (f *fakeGitlab) update(path string, attributes map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for k, v := range attributes {
+		f.objects[path][k] = v
+	}
+}
+
+// delete removes the object at the given canonical path and all objects below it, e.g. to simulate a deletion outside of Terraform.
+func (f *fakeGitlab) delete(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.deleteTree(path)
+}
+
+func (f *fakeGitlab) deleteTree(path string) {
+	for k := range f.objects {
+		if k == path || strings.HasPrefix(k, path+"/") {
+			delete(f.objects, k)
+		}
+	}
+}
+
+func (f *fakeGitlab) newID() int {
+	id := f.nextID
+	f.nextID++
+	return id
+}
+
+func (o fakeGitlabObject) copy() fakeGitlabObject {
+	c := make(fakeGitlabObject, len(o))
+	for k, v := range o {
+		c[k] = v
+	}
+	return c
+}
+
+// merge sets all attributes of the given request body, except the given ones, on the object.
+func (o fakeGitlabObject) merge(body fakeGitlabObject, except ...string) {
+	for k, v := range body {
+		skip := false
+		for _, e := range except {
+			skip = skip || k == e
+		}
+		if !skip {
+			o[k] = v
+		}
+	}
+}
+
+func (o fakeGitlabObject) string(key string) string {
+	s, _ := o[key].(string)
+	return s
+}
+
+func (o fakeGitlabObject) int(key string) int {
+	switch v := o[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		i, _ := strconv.Atoi(v)
+		return i
+	}
+	return 0
+}
+
+func (f *fakeGitlab) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	status, response, err := f.handle(r)
+	if err != nil {
+		status = http.StatusInternalServerError
+		response = map[string]interface{}{"message": err.Error()}
+		if e, ok := err.(*fakeGitlabError); ok {
+			status = e.status
+			response = map[string]interface{}{"message": e.message}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if status != http.StatusNoContent {
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func (f *fakeGitlab) handle(r *http.Request) (int, interface{}, error) {
+	if r.Header.Get("Authorization") != "Bearer "+fakeGitlabToken && r.Header.Get("Private-Token") != fakeGitlabToken {
+		return 0, nil, &fakeGitlabError{status: http.StatusUnauthorized, message: "401 Unauthorized"}
+	}
+
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/")
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(path, "/"), "/") {
+		unescaped, err := url.PathUnescape(s)
+		if err != nil {
+			return 0, nil, fakeGitlabBadRequest(err.Error())
+		}
+		segments = append(segments, unescaped)
+	}
+
+	body := fakeGitlabObject{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			return 0, nil, fakeGitlabBadRequest(err.Error())
+		}
+		for k, v := range r.MultipartForm.Value {
+			body[k] = fakeGitlabQueryValue(v[0])
+		}
+		// Uploaded avatars are not stored, only their URL is set.
+		if files := r.MultipartForm.File["avatar"]; len(files) > 0 {
+			body["avatar_url"] = f.server.URL + "/uploads/-/system/" + strings.Join(segments, "/") + "/avatar/" + files[0].Filename
+		}
+	} else if r.ContentLength != 0 && r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return 0, nil, fakeGitlabBadRequest(err.Error())
+		}
+		// An empty avatar removes it.
+		if avatar, ok := body["avatar"]; ok && avatar == "" {
+			delete(body, "avatar")
+			body["avatar_url"] = nil
+		}
+	}
+
+	// The go-gitlab client sends the options of all requests except POST and PUT as query parameters.
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		for k, v := range r.URL.Query() {
+			body[k] = fakeGitlabQueryValue(v[0])
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, route := range fakeGitlabRoutes {
+		if route.method != r.Method {
+			continue
+		}
+		if params, ok := matchFakeGitlabRoute(route.pattern, segments); ok {
+			status, response, err := route.handler(f, params, body, r.URL.Query())
+			if err != nil {
+				return 0, nil, err
+			}
+			// The response is encoded while holding the lock, because it may reference the stored objects.
+			encoded, err := json.Marshal(response)
+			return status, json.RawMessage(encoded), err
+		}
+	}
+	return 0, nil, fakeGitlabNotFound("Route")
+}
+
+// fakeGitlabQueryValue converts a query parameter value to the type it would have in a JSON body.
+func fakeGitlabQueryValue(value string) interface{} {
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// matchFakeGitlabRoute matches the path segments against a pattern like `projects/:id/hooks/:id`
+// and returns the values of the placeholders.
+func matchFakeGitlabRoute(pattern string, segments []string) ([]string, bool) {
+	parts := strings.Split(pattern, "/")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+
+	var params []string
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, ":"):
+			params = append(params, segments[i])
+		case part != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+var fakeGitlabRoutes []fakeGitlabRoute
+
+func init() {
+	fakeGitlabRoutes = []fakeGitlabRoute{
+		{"GET", "version", (*fakeGitlab).getVersion},
+		{"GET", "metadata", (*fakeGitlab).getVersion},
+		{"GET", "application/settings", (*fakeGitlab).getSettings},
+		{"GET", "user", (*fakeGitlab).getCurrentUser},
+		{"GET", "users/:id", (*fakeGitlab).getUser},
+		{"PUT", "users/:id", (*fakeGitlab).uploadUserAvatar},
+		{"GET", "namespaces/:id", (*fakeGitlab).getNamespace},
+
+		{"POST", "groups", (*fakeGitlab).createGroup},
+		{"GET", "groups/:id", (*fakeGitlab).getGroup},
+		{"PUT", "groups/:id", (*fakeGitlab).updateGroup},
+		{"DELETE", "groups/:id", (*fakeGitlab).deleteGroup},
+		{"GET", "groups/:id/subgroups", (*fakeGitlab).listSubgroups},
+		{"GET", "groups/:id/projects", (*fakeGitlab).listGroupProjects},
+		{"POST", "groups/:id/transfer", (*fakeGitlab).transferGroup},
+
+		{"POST", "projects", (*fakeGitlab).createProject},
+		{"GET", "projects/:id", (*fakeGitlab).getProject},
+		{"PUT", "projects/:id", (*fakeGitlab).updateProject},
+		{"DELETE", "projects/:id", (*fakeGitlab).deleteProject},
+		{"POST", "projects/:id/archive", fakeGitlabSetArchived(true)},
+		{"POST", "projects/:id/unarchive", fakeGitlabSetArchived(false)},
+		{"PUT", "projects/:id/transfer", (*fakeGitlab).transferProject},
+		{"POST", "projects/:id/fork", (*fakeGitlab).forkProject},
+		{"POST", "projects/:id/fork/:forked_from_id", (*fakeGitlab).createForkRelation},
+		{"DELETE", "projects/:id/fork", (*fakeGitlab).deleteForkRelation},
+		{"GET", "projects/:id/import", (*fakeGitlab).getImportStatus},
+		{"GET", "projects/:id/push_rule", (*fakeGitlab).getPushRule},
+		{"POST", "projects/:id/push_rule", (*fakeGitlab).setPushRule},
+		{"PUT", "projects/:id/push_rule", (*fakeGitlab).setPushRule},
+
+		{"POST", "projects/:id/repository/branches", (*fakeGitlab).createBranch},
+		{"GET", "projects/:id/repository/branches/:branch", (*fakeGitlab).getBranch},
+		{"DELETE", "projects/:id/repository/branches/:branch", (*fakeGitlab).deleteBranch},
+
+		{"GET", "projects/:id/protected_branches", fakeGitlabListChildren("projects", "protected_branches")},
+		{"POST", "projects/:id/protected_branches", (*fakeGitlab).protectBranch},
+		{"GET", "projects/:id/protected_branches/:branch", (*fakeGitlab).getProtectedBranch},
+		{"PATCH", "projects/:id/protected_branches/:branch", (*fakeGitlab).updateProtectedBranch},
+		{"DELETE", "projects/:id/protected_branches/:branch", (*fakeGitlab).unprotectBranch},
+
+		{"GET", "projects/:id/hooks", fakeGitlabListChildren("projects", "hooks")},
+		{"POST", "projects/:id/hooks", (*fakeGitlab).createHook},
+		{"GET", "projects/:id/hooks/:hook", (*fakeGitlab).getHook},
+		{"PUT", "projects/:id/hooks/:hook", (*fakeGitlab).updateHook},
+		{"DELETE", "projects/:id/hooks/:hook", (*fakeGitlab).deleteHook},
+	}
+
+	for _, parent := range []string{"projects", "groups"} {
+		fakeGitlabRoutes = append(fakeGitlabRoutes,
+			fakeGitlabRoute{"GET", parent + "/:id/members", fakeGitlabListChildren(parent, "members")},
+			fakeGitlabRoute{"POST", parent + "/:id/members", fakeGitlabAddMember(parent)},
+			fakeGitlabRoute{"GET", parent + "/:id/members/:user", fakeGitlabGetMember(parent)},
+			fakeGitlabRoute{"PUT", parent + "/:id/members/:user", fakeGitlabEditMember(parent)},
+			fakeGitlabRoute{"DELETE", parent + "/:id/members/:user", fakeGitlabRemoveMember(parent)},
+
+			fakeGitlabRoute{"GET", parent + "/:id/variables", fakeGitlabListChildren(parent, "variables")},
+			fakeGitlabRoute{"POST", parent + "/:id/variables", fakeGitlabCreateVariable(parent)},
+			fakeGitlabRoute{"GET", parent + "/:id/variables/:key", fakeGitlabGetVariable(parent)},
+			fakeGitlabRoute{"PUT", parent + "/:id/variables/:key", fakeGitlabUpdateVariable(parent)},
+			fakeGitlabRoute{"DELETE", parent + "/:id/variables/:key", fakeGitlabDeleteVariable(parent)},
+		)
+	}
+}
+
+func (f *fakeGitlab) getVersion(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	return http.StatusOK, fakeGitlabObject{"version": f.Version, "revision": "fake", "enterprise": f.Enterprise}, nil
+}
+
+func (f *fakeGitlab) getSettings(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	return http.StatusOK, fakeGitlabObject{"id": 1, "default_branch_protection": 2}, nil
+}
+
+func (f *fakeGitlab) getCurrentUser(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	return http.StatusOK, f.objects["users/1"], nil
+}
+
+func (f *fakeGitlab) getUser(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	user, ok := f.objects["users/"+params[0]]
+	if !ok {
+		return 0, nil, fakeGitlabNotFound("User")
+	}
+	return http.StatusOK, user, nil
+}
+
+// uploadUserAvatar only supports to upload the avatar of a user, like GitLab, which can't remove it,
+// but unlike GitLab, the other attributes of a user can't be modified.
+func (f *fakeGitlab) uploadUserAvatar(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	user, ok := f.objects["users/"+params[0]]
+	if !ok {
+		return 0, nil, fakeGitlabNotFound("User")
+	}
+	avatarURL, ok := body["avatar_url"].(string)
+	if len(body) != 1 || !ok {
+		return 0, nil, fakeGitlabBadRequest("only the avatar of a user can be uploaded")
+	}
+	user["avatar_url"] = avatarURL
+	return http.StatusOK, user, nil
+}
+
+// resolve returns the canonical path, e.g. `projects/2`, of the project or group with the given ID or full path.
+func (f *fakeGitlab) resolve(kind, id string) (string, fakeGitlabObject, error) {
+	if object, ok := f.objects[kind+"/"+id]; ok {
+		return kind + "/" + id, object, nil
+	}
+
+	pathAttribute := map[string]string{"projects": "path_with_namespace", "groups": "full_path"}[kind]
+	for path, object := range f.objects {
+		if strings.HasPrefix(path, kind+"/") && strings.Count(path, "/") == 1 && object.string(pathAttribute) == id {
+			return path, object, nil
+		}
+	}
+	return "", nil, fakeGitlabNotFound(map[string]string{"projects": "Project", "groups": "Group"}[kind])
+}
+
+func (f *fakeGitlab) getNamespace(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	id, err := strconv.Atoi(params[0])
+	if err != nil {
+		return 0, nil, fakeGitlabNotFound("Namespace")
+	}
+	namespace, err := f.namespace(id)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, namespace, nil
+}
+
+// namespace returns the group or personal namespace with the given ID.
+func (f *fakeGitlab) namespace(id int) (fakeGitlabObject, error) {
+	if group, ok := f.objects[fmt.Sprintf("groups/%d", id)]; ok {
+		return fakeGitlabObject{"id": id, "name": group["name"], "path": group["path"], "full_path": group["full_path"], "kind": "group"}, nil
+	}
+	if namespace, ok := f.objects[fmt.Sprintf("namespaces/%d", id)]; ok {
+		return namespace, nil
+	}
+	return nil, fakeGitlabNotFound("Namespace")
+}
+
+func (f *fakeGitlab) exists(kind, attribute, value string) bool {
+	for path, object := range f.objects {
+		if strings.HasPrefix(path, kind+"/") && strings.Count(path, "/") == 1 && object.string(attribute) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeGitlab) createGroup(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	if body.string("name") == "" || body.string("path") == "" {
+		return 0, nil, fakeGitlabBadRequest("name, path are missing")
+	}
+
+	group := fakeGitlabObject{
+		"description":               "",
+		"visibility":                "private",
+		"lfs_enabled":               true,
+		"request_access_enabled":    true,
+		"project_creation_level":    "developer",
+		"subgroup_creation_level":   "maintainer",
+		"two_factor_grace_period":   48,
+		"default_branch_protection": 2,
+		"parent_id":                 nil,
+	}
+	group.merge(body)
+	group["id"] = f.newID()
+	group["runners_token"] = "fake-runners-token"
+	if err := f.setGroupPath(group); err != nil {
+		return 0, nil, err
+	}
+
+	f.objects[fmt.Sprintf("groups/%d", group["id"])] = group
+	return http.StatusCreated, group, nil
+}
+
+func (f *fakeGitlab) listSubgroups(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	_, group, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	subgroups := []fakeGitlabObject{}
+	for _, path := range f.sortedPaths("groups/") {
+		if subgroup := f.objects[path]; strings.Count(path, "/") == 1 && subgroup.int("parent_id") == group.int("id") {
+			subgroups = append(subgroups, subgroup)
+		}
+	}
+	return http.StatusOK, subgroups, nil
+}
+
+func (f *fakeGitlab) listGroupProjects(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	_, group, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	projects := []fakeGitlabObject{}
+	for _, path := range f.sortedPaths("projects/") {
+		project := f.objects[path]
+		if namespace, _ := project["namespace"].(fakeGitlabObject); strings.Count(path, "/") == 1 && namespace.int("id") == group.int("id") {
+			projects = append(projects, f.projectResponse(project))
+		}
+	}
+	return http.StatusOK, projects, nil
+}
+
+// fakeGitlabListChildren lists the objects of the given collection of a project or group, e.g. its `members`.
+// All objects are returned on the first page.
+func fakeGitlabListChildren(kind, collection string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		children := []fakeGitlabObject{}
+		for _, childPath := range f.sortedPaths(path + "/" + collection + "/") {
+			children = append(children, f.objects[childPath])
+		}
+		return http.StatusOK, children, nil
+	}
+}
+
+// sortedPaths returns the canonical paths of all objects with the given prefix in order.
+func (f *fakeGitlab) sortedPaths(prefix string) []string {
+	var paths []string
+	for path := range f.objects {
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (f *fakeGitlab) setGroupPath(group fakeGitlabObject) error {
+	fullPath, fullName := group.string("path"), group.string("name")
+	if parentID := group.int("parent_id"); parentID != 0 {
+		parent, ok := f.objects[fmt.Sprintf("groups/%d", parentID)]
+		if !ok {
+			return fakeGitlabNotFound("Parent Group")
+		}
+		fullPath, fullName = parent.string("full_path")+"/"+fullPath, parent.string("full_name")+" / "+fullName
+	} else {
+		group["parent_id"] = nil
+	}
+
+	if fullPath != group.string("full_path") && (f.exists("groups", "full_path", fullPath) || f.exists("namespaces", "full_path", fullPath)) {
+		return fakeGitlabBadRequest(map[string][]string{"path": {"has already been taken"}})
+	}
+	group["full_path"], group["full_name"] = fullPath, fullName
+	group["web_url"] = f.server.URL + "/groups/" + fullPath
+	return nil
+}
+
+func (f *fakeGitlab) getGroup(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	_, group, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, group, nil
+}
+
+func (f *fakeGitlab) updateGroup(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	_, group, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	group.merge(body, "id", "parent_id", "full_path", "full_name")
+	if err := f.setGroupPath(group); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, group, nil
+}
+
+func (f *fakeGitlab) transferGroup(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	_, group, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	group["parent_id"] = body["group_id"]
+	if err := f.setGroupPath(group); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusCreated, group, nil
+}
+
+func (f *fakeGitlab) deleteGroup(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	path, group, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	f.deleteTree(path)
+	for projectPath, project := range f.objects {
+		if strings.HasPrefix(projectPath, "projects/") && strings.Count(projectPath, "/") == 1 && strings.HasPrefix(project.string("path_with_namespace"), group.string("full_path")+"/") {
+			f.deleteTree(projectPath)
+		}
+	}
+	return http.StatusAccepted, fakeGitlabObject{"message": "202 Accepted"}, nil
+}
+
+func (f *fakeGitlab) createProject(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	if body.string("name") == "" && body.string("path") == "" {
+		return 0, nil, fakeGitlabBadRequest("name, path are missing, at least one parameter must be provided")
+	}
+
+	project := fakeGitlabObject{
+		"description":                         "",
+		"visibility":                          "private",
+		"default_branch":                      nil,
+		"archived":                            false,
+		"import_status":                       "none",
+		"issues_enabled":                      true,
+		"merge_requests_enabled":              true,
+		"jobs_enabled":                        true,
+		"wiki_enabled":                        true,
+		"snippets_enabled":                    true,
+		"container_registry_enabled":          true,
+		"lfs_enabled":                         true,
+		"packages_enabled":                    true,
+		"request_access_enabled":              true,
+		"shared_runners_enabled":              true,
+		"merge_method":                        "merge",
+		"squash_option":                       "default_off",
+		"pages_access_level":                  "private",
+		"ci_forward_deployment_enabled":       true,
+		"printing_merge_request_link_enabled": true,
+		"topics":                              []interface{}{},
+		"tag_list":                            []interface{}{},
+	}
+	for _, feature := range []string{"issues", "repository", "merge_requests", "forking", "wiki", "builds", "snippets", "operations", "analytics", "container_registry", "security_and_compliance", "requirements"} {
+		project[feature+"_access_level"] = "enabled"
+	}
+	project.merge(body, "namespace_id", "initialize_with_readme")
+	if project.string("name") == "" {
+		project["name"] = project["path"]
+	}
+	if project.string("path") == "" {
+		project["path"] = strings.ToLower(strings.ReplaceAll(project.string("name"), " ", "-"))
+	}
+	project["id"] = f.newID()
+
+	namespaceID := body.int("namespace_id")
+	if namespaceID == 0 {
+		namespaceID = 1
+	}
+	if err := f.setProjectNamespace(project, namespaceID); err != nil {
+		return 0, nil, err
+	}
+
+	path := fmt.Sprintf("projects/%d", project["id"])
+	f.objects[path] = project
+
+	if initialize, _ := body["initialize_with_readme"].(bool); initialize {
+		defaultBranch := project.string("default_branch")
+		if defaultBranch == "" {
+			defaultBranch = "main"
+		}
+		project["default_branch"] = defaultBranch
+		f.objects[path+"/repository/branches/"+defaultBranch] = fakeGitlabObject{
+			"name":   defaultBranch,
+			"commit": fakeGitlabCommit("Initial commit"),
+		}
+		// GitLab protects the default branch of new projects.
+		f.objects[path+"/protected_branches/"+defaultBranch] = fakeGitlabProtectedBranch(f.newID(), defaultBranch, fakeGitlabObject{})
+	}
+
+	response := f.projectResponse(project)
+	if project.string("import_url") != "" {
+		// The import is done right away, but like GitLab, the response says that it has only been scheduled.
+		project["import_status"], project["import_error"] = "finished", nil
+		if f.ImportError != "" {
+			project["import_status"], project["import_error"] = "failed", f.ImportError
+		}
+		response["import_status"] = "scheduled"
+	}
+	return http.StatusCreated, response, nil
+}
+
+func (f *fakeGitlab) setProjectNamespace(project fakeGitlabObject, namespaceID int) error {
+	namespace, err := f.namespace(namespaceID)
+	if err != nil {
+		return err
+	}
+
+	pathWithNamespace := namespace.string("full_path") + "/" + project.string("path")
+	if pathWithNamespace != project.string("path_with_namespace") && f.exists("projects", "path_with_namespace", pathWithNamespace) {
+		return fakeGitlabBadRequest(map[string][]string{"path": {"has already been taken"}})
+	}
+
+	project["namespace"] = namespace
+	project["path_with_namespace"] = pathWithNamespace
+	project["name_with_namespace"] = namespace.string("name") + " / " + project.string("name")
+	project["web_url"] = f.server.URL + "/" + pathWithNamespace
+	project["http_url_to_repo"] = f.server.URL + "/" + pathWithNamespace + ".git"
+	project["ssh_url_to_repo"] = "git@" + strings.TrimPrefix(f.server.URL, "http://") + ":" + pathWithNamespace + ".git"
+	return nil
+}
+
+// projectResponse returns the project with the attributes which are derived from other objects.
+func (f *fakeGitlab) projectResponse(project fakeGitlabObject) fakeGitlabObject {
+	response := project.copy()
+	if project.string("default_branch") == "" {
+		response["empty_repo"] = true
+	}
+	return response
+}
+
+func (f *fakeGitlab) getProject(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	path, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	if transfer, ok := f.transfers[path]; ok {
+		if transfer.reads--; transfer.reads < 0 {
+			delete(f.transfers, path)
+			if err := f.setProjectNamespace(project, transfer.namespaceID); err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+	return http.StatusOK, f.projectResponse(project), nil
+}
+
+func (f *fakeGitlab) updateProject(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	path, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if defaultBranch := body.string("default_branch"); defaultBranch != "" {
+		if _, ok := f.objects[path+"/repository/branches/"+defaultBranch]; !ok {
+			return 0, nil, fakeGitlabBadRequest(map[string][]string{"base": {"Could not change HEAD: branch '" + defaultBranch + "' does not exist"}})
+		}
+	}
+
+	project.merge(body, "id", "namespace", "path_with_namespace", "archived")
+	if err := f.setProjectNamespace(project, project["namespace"].(fakeGitlabObject).int("id")); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, f.projectResponse(project), nil
+}
+
+func (f *fakeGitlab) deleteProject(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	path, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	f.deleteTree(path)
+	return http.StatusAccepted, fakeGitlabObject{"message": "202 Accepted"}, nil
+}
+
+func fakeGitlabSetArchived(archived bool) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		_, project, err := f.resolve("projects", params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		project["archived"] = archived
+		return http.StatusCreated, f.projectResponse(project), nil
+	}
+}
+
+func (f *fakeGitlab) transferProject(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	path, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	namespaceID := body.int("namespace")
+	if namespaceID == 0 {
+		_, group, err := f.resolve("groups", body.string("namespace"))
+		if err != nil {
+			return 0, nil, err
+		}
+		namespaceID = group.int("id")
+	}
+	if f.TransferReads > 0 {
+		if _, err := f.namespace(namespaceID); err != nil {
+			return 0, nil, err
+		}
+		f.transfers[path] = &fakeGitlabTransfer{namespaceID: namespaceID, reads: f.TransferReads}
+		return http.StatusOK, f.projectResponse(project), nil
+	}
+	if err := f.setProjectNamespace(project, namespaceID); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, f.projectResponse(project), nil
+}
+
+// forkProject copies the project with its branches into the namespace given by `namespace_id` or `namespace_path`.
+// The fork is ready right away, but like GitLab, the response says that its repository is still being copied.
+func (f *fakeGitlab) forkProject(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	sourcePath, source, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	namespaceID := body.int("namespace_id")
+	if namespacePath := body.string("namespace_path"); namespacePath != "" {
+		for path, namespace := range f.objects {
+			if (strings.HasPrefix(path, "groups/") || strings.HasPrefix(path, "namespaces/")) && strings.Count(path, "/") == 1 && namespace.string("full_path") == namespacePath {
+				namespaceID = namespace.int("id")
+			}
+		}
+		if namespaceID == 0 {
+			return 0, nil, fakeGitlabNotFound("Target Namespace")
+		}
+	}
+	if namespaceID == 0 {
+		namespaceID = 1
+	}
+
+	fork := source.copy()
+	fork.merge(body, "namespace_id", "namespace_path", "branches")
+	fork["id"] = f.newID()
+	fork["archived"] = false
+	fork["forked_from_project"] = fakeGitlabObject{"id": source["id"], "path_with_namespace": source["path_with_namespace"]}
+	fork["import_status"] = "finished"
+	delete(fork, "path_with_namespace")
+	if err := f.setProjectNamespace(fork, namespaceID); err != nil {
+		return 0, nil, err
+	}
+
+	path := fmt.Sprintf("projects/%d", fork["id"])
+	f.objects[path] = fork
+	for _, branchPath := range f.sortedPaths(sourcePath + "/repository/branches/") {
+		branch := f.objects[branchPath]
+		if branches := body.string("branches"); branches == "" || contains(strings.Split(branches, ","), branch.string("name")) {
+			f.objects[path+"/repository/branches/"+branch.string("name")] = branch.copy()
+		}
+	}
+
+	response := f.projectResponse(fork)
+	response["import_status"] = "scheduled"
+	return http.StatusCreated, response, nil
+}
+
+func (f *fakeGitlab) createForkRelation(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	_, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	_, source, err := f.resolve("projects", params[1])
+	if err != nil {
+		return 0, nil, err
+	}
+	if project["forked_from_project"] != nil {
+		return 0, nil, &fakeGitlabError{status: http.StatusConflict, message: "Project already forked"}
+	}
+
+	project["forked_from_project"] = fakeGitlabObject{"id": source["id"], "path_with_namespace": source["path_with_namespace"]}
+	return http.StatusCreated, fakeGitlabObject{"forked_to_project_id": project["id"], "forked_from_project_id": source["id"]}, nil
+}
+
+func (f *fakeGitlab) deleteForkRelation(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	_, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	if project["forked_from_project"] == nil {
+		return http.StatusNotModified, nil, nil
+	}
+
+	delete(project, "forked_from_project")
+	return http.StatusNoContent, nil, nil
+}
+
+func (f *fakeGitlab) getImportStatus(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	_, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, fakeGitlabObject{"id": project["id"], "import_status": project["import_status"], "import_error": project["import_error"]}, nil
+}
+
+func (f *fakeGitlab) getPushRule(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	path, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	pushRule, ok := f.objects[path+"/push_rule"]
+	if !ok {
+		// GitLab responds with `null` if no push rules have been set.
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusOK, pushRule, nil
+}
+
+func (f *fakeGitlab) setPushRule(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	path, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	if !f.Enterprise {
+		return 0, nil, fakeGitlabNotFound("")
+	}
+
+	pushRule, ok := f.objects[path+"/push_rule"]
+	if !ok {
+		pushRule = fakeGitlabObject{"id": f.newID(), "project_id": project["id"]}
+		f.objects[path+"/push_rule"] = pushRule
+	}
+	pushRule.merge(body, "id", "project_id")
+	return http.StatusCreated, pushRule, nil
+}
+
+func fakeGitlabCommit(message string) fakeGitlabObject {
+	sum := sha1.Sum([]byte(message + time.Now().String()))
+	id := hex.EncodeToString(sum[:])
+	return fakeGitlabObject{
+		"id":              id,
+		"short_id":        id[:8],
+		"title":           message,
+		"message":         message,
+		"author_name":     "Administrator",
+		"author_email":    "admin@example.com",
+		"committer_name":  "Administrator",
+		"committer_email": "admin@example.com",
+		"authored_date":   "2022-01-01T00:00:00.000Z",
+		"committed_date":  "2022-01-01T00:00:00.000Z",
+		"parent_ids":      []interface{}{},
+	}
+}
+
+// branchResponse returns the branch with the attributes which are derived from the project and the protected branches.
+func (f *fakeGitlab) branchResponse(projectPath string, project, branch fakeGitlabObject) fakeGitlabObject {
+	response := branch.copy()
+	_, protected := f.objects[projectPath+"/protected_branches/"+branch.string("name")]
+	response["protected"] = protected
+	response["default"] = project.string("default_branch") == branch.string("name")
+	response["merged"] = false
+	response["can_push"] = true
+	response["developers_can_push"] = false
+	response["developers_can_merge"] = false
+	response["web_url"] = project.string("web_url") + "/-/tree/" + branch.string("name")
+	return response
+}
+
+func (f *fakeGitlab) createBranch(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	name, ref := body.string("branch"), body.string("ref")
+	if _, ok := f.objects[projectPath+"/repository/branches/"+name]; ok {
+		return 0, nil, fakeGitlabBadRequest("Branch already exists")
+	}
+	refBranch, ok := f.objects[projectPath+"/repository/branches/"+ref]
+	if !ok {
+		return 0, nil, fakeGitlabBadRequest("Invalid reference name: " + ref)
+	}
+
+	branch := fakeGitlabObject{"name": name, "commit": refBranch["commit"]}
+	f.objects[projectPath+"/repository/branches/"+name] = branch
+	if project.string("default_branch") == "" {
+		project["default_branch"] = name
+	}
+	return http.StatusCreated, f.branchResponse(projectPath, project, branch), nil
+}
+
+func (f *fakeGitlab) getBranch(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	branch, ok := f.objects[projectPath+"/repository/branches/"+params[1]]
+	if !ok {
+		return 0, nil, fakeGitlabNotFound("Branch")
+	}
+	return http.StatusOK, f.branchResponse(projectPath, project, branch), nil
+}
+
+func (f *fakeGitlab) deleteBranch(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if _, ok := f.objects[projectPath+"/repository/branches/"+params[1]]; !ok {
+		return 0, nil, fakeGitlabNotFound("Branch")
+	}
+	if project.string("default_branch") == params[1] {
+		return 0, nil, fakeGitlabBadRequest("The default branch of a project cannot be deleted.")
+	}
+	delete(f.objects, projectPath+"/repository/branches/"+params[1])
+	return http.StatusNoContent, nil, nil
+}
+
+var fakeGitlabAccessLevelDescriptions = map[int]string{
+	0:  "No one",
+	30: "Developers + Maintainers",
+	40: "Maintainers",
+	60: "Admins",
+}
+
+func fakeGitlabProtectedBranch(id int, name string, body fakeGitlabObject) fakeGitlabObject {
+	accessLevels := func(kind string) []interface{} {
+		level := 40
+		if _, ok := body[kind+"_access_level"]; ok {
+			level = body.int(kind + "_access_level")
+		}
+		levels := []interface{}{fakeGitlabObject{"access_level": level, "access_level_description": fakeGitlabAccessLevelDescriptions[level], "user_id": nil, "group_id": nil}}
+
+		allowed, _ := body["allowed_to_"+kind].([]interface{})
+		for _, a := range allowed {
+			entry := fakeGitlabObject(a.(map[string]interface{}))
+			description := fmt.Sprintf("User %d", entry.int("user_id"))
+			if entry.int("group_id") != 0 {
+				description = fmt.Sprintf("Group %d", entry.int("group_id"))
+			}
+			levels = append(levels, fakeGitlabObject{"access_level": 40, "access_level_description": description, "user_id": entry["user_id"], "group_id": entry["group_id"]})
+		}
+		return levels
+	}
+
+	return fakeGitlabObject{
+		"id":                           id,
+		"name":                         name,
+		"push_access_levels":           accessLevels("push"),
+		"merge_access_levels":          accessLevels("merge"),
+		"unprotect_access_levels":      accessLevels("unprotect"),
+		"allow_force_push":             body["allow_force_push"] == true,
+		"code_owner_approval_required": body["code_owner_approval_required"] == true,
+	}
+}
+
+func (f *fakeGitlab) protectBranch(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	name := body.string("name")
+	if _, ok := f.objects[projectPath+"/protected_branches/"+name]; ok {
+		return 0, nil, &fakeGitlabError{status: http.StatusConflict, message: "Protected branch '" + name + "' already exists"}
+	}
+
+	protectedBranch := fakeGitlabProtectedBranch(f.newID(), name, body)
+	f.objects[projectPath+"/protected_branches/"+name] = protectedBranch
+	return http.StatusCreated, protectedBranch, nil
+}
+
+func (f *fakeGitlab) getProtectedBranch(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	protectedBranch, ok := f.objects[projectPath+"/protected_branches/"+params[1]]
+	if !ok {
+		return 0, nil, fakeGitlabNotFound("")
+	}
+	return http.StatusOK, protectedBranch, nil
+}
+
+func (f *fakeGitlab) updateProtectedBranch(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	protectedBranch, ok := f.objects[projectPath+"/protected_branches/"+params[1]]
+	if !ok {
+		return 0, nil, fakeGitlabNotFound("")
+	}
+	for _, attribute := range []string{"allow_force_push", "code_owner_approval_required"} {
+		if v, ok := body[attribute]; ok {
+			protectedBranch[attribute] = v
+		}
+	}
+	return http.StatusOK, protectedBranch, nil
+}
+
+func (f *fakeGitlab) unprotectBranch(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if _, ok := f.objects[projectPath+"/protected_branches/"+params[1]]; !ok {
+		return 0, nil, fakeGitlabNotFound("")
+	}
+	delete(f.objects, projectPath+"/protected_branches/"+params[1])
+	return http.StatusNoContent, nil, nil
+}
+
+func (f *fakeGitlab) createHook(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	if body.string("url") == "" {
+		return 0, nil, fakeGitlabBadRequest("url is missing")
+	}
+
+	hook := fakeGitlabObject{
+		"push_events":             true,
+		"enable_ssl_verification": true,
+	}
+	hook.merge(body, "token")
+	hook["id"] = f.newID()
+	hook["project_id"] = project["id"]
+	hook["created_at"] = "2022-01-01T00:00:00.000Z"
+
+	f.objects[fmt.Sprintf("%s/hooks/%d", projectPath, hook["id"])] = hook
+	return http.StatusCreated, hook, nil
+}
+
+func (f *fakeGitlab) getHook(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	hook, ok := f.objects[projectPath+"/hooks/"+params[1]]
+	if !ok {
+		return 0, nil, fakeGitlabNotFound("")
+	}
+	return http.StatusOK, hook, nil
+}
+
+func (f *fakeGitlab) updateHook(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	hook, ok := f.objects[projectPath+"/hooks/"+params[1]]
+	if !ok {
+		return 0, nil, fakeGitlabNotFound("")
+	}
+	hook.merge(body, "id", "project_id", "token")
+	return http.StatusOK, hook, nil
+}
+
+func (f *fakeGitlab) deleteHook(params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+	projectPath, _, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if _, ok := f.objects[projectPath+"/hooks/"+params[1]]; !ok {
+		return 0, nil, fakeGitlabNotFound("")
+	}
+	delete(f.objects, projectPath+"/hooks/"+params[1])
+	return http.StatusNoContent, nil, nil
+}
+
+func fakeGitlabAddMember(kind string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		user, ok := f.objects[fmt.Sprintf("users/%d", body.int("user_id"))]
+		if !ok {
+			return 0, nil, fakeGitlabNotFound("User")
+		}
+		memberPath := fmt.Sprintf("%s/members/%d", path, body.int("user_id"))
+		if _, ok := f.objects[memberPath]; ok {
+			return 0, nil, &fakeGitlabError{status: http.StatusConflict, message: "Member already exists"}
+		}
+
+		member := fakeGitlabObject{
+			"id":           user["id"],
+			"username":     user["username"],
+			"name":         user["name"],
+			"state":        user["state"],
+			"web_url":      user["web_url"],
+			"access_level": body.int("access_level"),
+			"expires_at":   nil,
+		}
+		if expiresAt := body.string("expires_at"); expiresAt != "" {
+			member["expires_at"] = expiresAt
+		}
+		f.objects[memberPath] = member
+		return http.StatusCreated, member, nil
+	}
+}
+
+func fakeGitlabGetMember(kind string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		member, ok := f.objects[path+"/members/"+params[1]]
+		if !ok {
+			return 0, nil, fakeGitlabNotFound("Member")
+		}
+		return http.StatusOK, member, nil
+	}
+}
+
+func fakeGitlabEditMember(kind string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		member, ok := f.objects[path+"/members/"+params[1]]
+		if !ok {
+			return 0, nil, fakeGitlabNotFound("Member")
+		}
+		if _, ok := body["access_level"]; ok {
+			member["access_level"] = body.int("access_level")
+		}
+		if _, ok := body["expires_at"]; ok {
+			member["expires_at"] = nil
+			if expiresAt := body.string("expires_at"); expiresAt != "" {
+				member["expires_at"] = expiresAt
+			}
+		}
+		return http.StatusOK, member, nil
+	}
+}
+
+func fakeGitlabRemoveMember(kind string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if _, ok := f.objects[path+"/members/"+params[1]]; !ok {
+			return 0, nil, fakeGitlabNotFound("Member")
+		}
+		delete(f.objects, path+"/members/"+params[1])
+		return http.StatusNoContent, nil, nil
+	}
+}
+
+// findVariable returns the canonical path of the variable with the given key and the environment scope of the
+// `filter[environment_scope]` query parameter. Without the filter, the key must be unique.
+func (f *fakeGitlab) findVariable(path, key string, query url.Values) (string, error) {
+	if scope := query.Get("filter[environment_scope]"); scope != "" {
+		variablePath := path + "/variables/" + key + "/" + scope
+		if _, ok := f.objects[variablePath]; !ok {
+			return "", fakeGitlabNotFound("Variable")
+		}
+		return variablePath, nil
+	}
+
+	var matches []string
+	for variablePath := range f.objects {
+		if strings.HasPrefix(variablePath, path+"/variables/"+key+"/") {
+			matches = append(matches, variablePath)
+		}
+	}
+	sort.Strings(matches)
+	switch len(matches) {
+	case 0:
+		return "", fakeGitlabNotFound("Variable")
+	case 1:
+		return matches[0], nil
+	}
+	return "", &fakeGitlabError{status: http.StatusConflict, message: "There are multiple variables with provided parameters. Please use 'filter[environment_scope]'"}
+}
+
+func fakeGitlabCreateVariable(kind string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		variable := fakeGitlabObject{
+			"variable_type":     "env_var",
+			"protected":         false,
+			"masked":            false,
+			"environment_scope": "*",
+		}
+		variable.merge(body)
+		if variable.string("key") == "" {
+			return 0, nil, fakeGitlabBadRequest("key is missing")
+		}
+
+		variablePath := path + "/variables/" + variable.string("key") + "/" + variable.string("environment_scope")
+		if _, ok := f.objects[variablePath]; ok {
+			return 0, nil, fakeGitlabBadRequest(map[string][]string{"key": {"(" + variable.string("key") + ") has already been taken"}})
+		}
+		f.objects[variablePath] = variable
+		return http.StatusCreated, variable, nil
+	}
+}
+
+func fakeGitlabGetVariable(kind string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		variablePath, err := f.findVariable(path, params[1], query)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, f.objects[variablePath], nil
+	}
+}
+
+func fakeGitlabUpdateVariable(kind string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		variablePath, err := f.findVariable(path, params[1], query)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		variable := f.objects[variablePath]
+		variable.merge(body, "key", "filter")
+		delete(f.objects, variablePath)
+		f.objects[path+"/variables/"+variable.string("key")+"/"+variable.string("environment_scope")] = variable
+		return http.StatusOK, variable, nil
+	}
+}
+
+func fakeGitlabDeleteVariable(kind string) func(*fakeGitlab, []string, fakeGitlabObject, url.Values) (int, interface{}, error) {
+	return func(f *fakeGitlab, params []string, body fakeGitlabObject, query url.Values) (int, interface{}, error) {
+		path, _, err := f.resolve(kind, params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		variablePath, err := f.findVariable(path, params[1], query)
+		if err != nil {
+			return 0, nil, err
+		}
+		delete(f.objects, variablePath)
+		return http.StatusNoContent, nil, nil
+	}
+}
@@ -0,0 +1,573 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/xanzy/go-gitlab"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// GenerateOptions configures Generate.
+type GenerateOptions struct {
+	// Config is the configuration of the client used to read the objects.
+	Config Config
+	// Group is the ID or full path of the top-level group to generate the configuration for.
+	Group string
+	// Dir is the directory the files are written to.
+	Dir string
+	// ImportScript writes an `import.sh` script with `terraform import` commands instead of an `imports.tf` file with `import` blocks.
+	ImportScript bool
+}
+
+// Generate writes the Terraform configuration for an existing group, its subgroups and projects,
+// and their members, variables, hooks, labels, badges and protected branches into a `gitlab.tf` file.
+// The objects can then be imported with the `import` blocks of an `imports.tf` file or with an `import.sh` script.
+//
+// Every object is read by the `Read` function of its resource with the ID it is imported with,
+// thus the generated configuration is exactly what the provider would read after the import.
+// Only the attributes which differ from their defaults are written, and references to generated
+// groups and projects use the resource address instead of the ID. Sensitive attributes reference
+// variables declared in a `variables.tf` file instead, whose values have to be set before applying.
+func Generate(ctx context.Context, opts GenerateOptions) error {
+	client, err := opts.Config.Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	files := []string{filepath.Join(opts.Dir, "gitlab.tf"), filepath.Join(opts.Dir, "imports.tf"), filepath.Join(opts.Dir, "variables.tf")}
+	if opts.ImportScript {
+		files[1] = filepath.Join(opts.Dir, "import.sh")
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			return fmt.Errorf("the file %s already exists", file)
+		}
+	}
+
+	g := &generator{
+		meta:          newProviderMeta(client),
+		resources:     New("generate")().ResourcesMap,
+		config:        hclwrite.NewEmptyFile(),
+		names:         make(map[string]bool),
+		references:    make(map[string]string),
+		variableNames: make(map[string]bool),
+	}
+	if err := g.generateGroup(ctx, opts.Group); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(files[0], g.config.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if len(g.variables) > 0 {
+		attributes := make([]string, 0, len(g.variables))
+		for _, v := range g.variables {
+			attributes = append(attributes, fmt.Sprintf("%s (var.%s)", v.attribute, v.name))
+		}
+		log.Printf("[WARN] the values of the following sensitive attributes are not written, set the variables they reference before applying: %s", strings.Join(attributes, ", "))
+		if err := os.WriteFile(files[2], g.variableBlocks(), 0o644); err != nil {
+			return err
+		}
+	}
+	if opts.ImportScript {
+		return os.WriteFile(files[1], g.importScript(), 0o755) // nolint:gosec // the script is meant to be executed
+	}
+	return os.WriteFile(files[1], g.importBlocks(), 0o644)
+}
+
+type generator struct {
+	meta      *providerMeta
+	resources map[string]*schema.Resource
+	config    *hclwrite.File
+	imports   []generatedImport
+
+	// names are the resource names which are already used.
+	names map[string]bool
+	// references maps the IDs of the generated groups and projects to their resource address.
+	references map[string]string
+	// variables are referenced by sensitive attributes instead of their value.
+	variables     []generatedVariable
+	variableNames map[string]bool
+}
+
+type generatedImport struct {
+	address string
+	id      string
+}
+
+type generatedVariable struct {
+	name string
+	// attribute is the address of the sensitive attribute, e.g. `gitlab_project_variable.foo.value`.
+	attribute string
+	schema    *schema.Schema
+}
+
+func (g *generator) generateGroup(ctx context.Context, id string) error {
+	client := g.meta.client
+
+	group, _, err := client.Groups.GetGroup(id, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get group %s: %w", id, err)
+	}
+	groupID := strconv.Itoa(group.ID)
+	name, err := g.generate(ctx, "gitlab_group", group.FullPath, groupID)
+	if err != nil || name == "" {
+		return err
+	}
+
+	if err := g.list("members of group "+group.FullPath, func(page int) (int, error) {
+		members, resp, err := client.Groups.ListGroupMembers(group.ID, &gitlab.ListGroupMembersOptions{ListOptions: gitlab.ListOptions{Page: page, PerPage: 100}}, gitlab.WithContext(ctx))
+		for _, member := range members {
+			if _, err := g.generate(ctx, "gitlab_group_membership", name+"_"+member.Username, fmt.Sprintf("%s:%d", groupID, member.ID)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("variables of group "+group.FullPath, func(page int) (int, error) {
+		variables, resp, err := client.GroupVariables.ListVariables(group.ID, &gitlab.ListGroupVariablesOptions{Page: page, PerPage: 100}, gitlab.WithContext(ctx))
+		for _, variable := range variables {
+			if _, err := g.generate(ctx, "gitlab_group_variable", variableResourceName(name, variable.Key, variable.EnvironmentScope), fmt.Sprintf("%s:%s:%s", groupID, variable.Key, variable.EnvironmentScope)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("hooks of group "+group.FullPath, func(page int) (int, error) {
+		hooks, resp, err := client.Groups.ListGroupHooks(group.ID, &gitlab.ListGroupHooksOptions{Page: page, PerPage: 100}, gitlab.WithContext(ctx))
+		for _, hook := range hooks {
+			if _, err := g.generate(ctx, "gitlab_group_hook", fmt.Sprintf("%s_hook_%d", name, hook.ID), fmt.Sprintf("%s:%d", groupID, hook.ID)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("labels of group "+group.FullPath, func(page int) (int, error) {
+		labels, resp, err := client.GroupLabels.ListGroupLabels(group.ID, &gitlab.ListGroupLabelsOptions{ListOptions: gitlab.ListOptions{Page: page, PerPage: 100}, OnlyGroupLabels: gitlab.Bool(true), IncludeAncestorGroups: gitlab.Bool(false)}, gitlab.WithContext(ctx))
+		for _, label := range labels {
+			if _, err := g.generate(ctx, "gitlab_group_label", name+"_label_"+label.Name, groupID+":"+label.Name); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("badges of group "+group.FullPath, func(page int) (int, error) {
+		badges, resp, err := client.GroupBadges.ListGroupBadges(group.ID, &gitlab.ListGroupBadgesOptions{Page: page, PerPage: 100}, gitlab.WithContext(ctx))
+		for _, badge := range badges {
+			if _, err := g.generate(ctx, "gitlab_group_badge", fmt.Sprintf("%s_badge_%d", name, badge.ID), fmt.Sprintf("%s:%d", groupID, badge.ID)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("projects of group "+group.FullPath, func(page int) (int, error) {
+		projects, resp, err := client.Groups.ListGroupProjects(group.ID, &gitlab.ListGroupProjectsOptions{ListOptions: gitlab.ListOptions{Page: page, PerPage: 100}, WithShared: gitlab.Bool(false)}, gitlab.WithContext(ctx))
+		for _, project := range projects {
+			if err := g.generateProject(ctx, project); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	return g.list("subgroups of group "+group.FullPath, func(page int) (int, error) {
+		subgroups, resp, err := client.Groups.ListSubGroups(group.ID, &gitlab.ListSubGroupsOptions{ListOptions: gitlab.ListOptions{Page: page, PerPage: 100}}, gitlab.WithContext(ctx))
+		for _, subgroup := range subgroups {
+			if err := g.generateGroup(ctx, strconv.Itoa(subgroup.ID)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	})
+}
+
+func (g *generator) generateProject(ctx context.Context, project *gitlab.Project) error {
+	client := g.meta.client
+
+	projectID := strconv.Itoa(project.ID)
+	name, err := g.generate(ctx, "gitlab_project", project.PathWithNamespace, projectID)
+	if err != nil || name == "" {
+		return err
+	}
+
+	if err := g.list("members of project "+project.PathWithNamespace, func(page int) (int, error) {
+		members, resp, err := client.ProjectMembers.ListProjectMembers(project.ID, &gitlab.ListProjectMembersOptions{ListOptions: gitlab.ListOptions{Page: page, PerPage: 100}}, gitlab.WithContext(ctx))
+		for _, member := range members {
+			if _, err := g.generate(ctx, "gitlab_project_membership", name+"_"+member.Username, fmt.Sprintf("%s:%d", projectID, member.ID)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("variables of project "+project.PathWithNamespace, func(page int) (int, error) {
+		variables, resp, err := client.ProjectVariables.ListVariables(project.ID, &gitlab.ListProjectVariablesOptions{Page: page, PerPage: 100}, gitlab.WithContext(ctx))
+		for _, variable := range variables {
+			if _, err := g.generate(ctx, "gitlab_project_variable", variableResourceName(name, variable.Key, variable.EnvironmentScope), fmt.Sprintf("%s:%s:%s", projectID, variable.Key, variable.EnvironmentScope)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("hooks of project "+project.PathWithNamespace, func(page int) (int, error) {
+		hooks, resp, err := client.Projects.ListProjectHooks(project.ID, &gitlab.ListProjectHooksOptions{Page: page, PerPage: 100}, gitlab.WithContext(ctx))
+		for _, hook := range hooks {
+			if _, err := g.generate(ctx, "gitlab_project_hook", fmt.Sprintf("%s_hook_%d", name, hook.ID), fmt.Sprintf("%s:%d", projectID, hook.ID)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("labels of project "+project.PathWithNamespace, func(page int) (int, error) {
+		labels, resp, err := client.Labels.ListLabels(project.ID, &gitlab.ListLabelsOptions{ListOptions: gitlab.ListOptions{Page: page, PerPage: 100}, IncludeAncestorGroups: gitlab.Bool(false)}, gitlab.WithContext(ctx))
+		for _, label := range labels {
+			if !label.IsProjectLabel {
+				continue
+			}
+			if _, err := g.generate(ctx, "gitlab_label", name+"_label_"+label.Name, projectID+":"+label.Name); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	if err := g.list("badges of project "+project.PathWithNamespace, func(page int) (int, error) {
+		badges, resp, err := client.ProjectBadges.ListProjectBadges(project.ID, &gitlab.ListProjectBadgesOptions{ListOptions: gitlab.ListOptions{Page: page, PerPage: 100}}, gitlab.WithContext(ctx))
+		for _, badge := range badges {
+			// The badges of the groups are listed as well, but they are managed by the group.
+			if badge.Kind != "project" {
+				continue
+			}
+			if _, err := g.generate(ctx, "gitlab_project_badge", fmt.Sprintf("%s_badge_%d", name, badge.ID), fmt.Sprintf("%s:%d", projectID, badge.ID)); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	}); err != nil {
+		return err
+	}
+
+	return g.list("protected branches of project "+project.PathWithNamespace, func(page int) (int, error) {
+		branches, resp, err := client.ProtectedBranches.ListProtectedBranches(project.ID, &gitlab.ListProtectedBranchesOptions{Page: page, PerPage: 100}, gitlab.WithContext(ctx))
+		for _, branch := range branches {
+			if _, err := g.generate(ctx, "gitlab_branch_protection", name+"_"+branch.Name, projectID+":"+branch.Name); err != nil {
+				return 0, err
+			}
+		}
+		return nextPage(resp), err
+	})
+}
+
+// list calls the given function for every page of a list, starting with the first page.
+// Lists which aren't available, e.g. because they require a higher license tier, are skipped.
+func (g *generator) list(what string, listPage func(page int) (int, error)) error {
+	for page := 1; page != 0; {
+		next, err := listPage(page)
+		if err != nil {
+			if errResponse, ok := err.(*gitlab.ErrorResponse); ok && (isGone(err) || errResponse.Response.StatusCode == http.StatusForbidden) {
+				log.Printf("[WARN] skipping the %s, because they are not available: %v", what, err)
+				return nil
+			}
+			return fmt.Errorf("failed to list the %s: %w", what, err)
+		}
+		page = next
+	}
+	return nil
+}
+
+func nextPage(resp *gitlab.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.NextPage
+}
+
+func variableResourceName(parent, key, environmentScope string) string {
+	if environmentScope == "*" {
+		return parent + "_" + key
+	}
+	return parent + "_" + key + "_" + environmentScope
+}
+
+// generate reads the object with the given import ID like the resource of the given type would do after an import
+// and adds its configuration and import. It returns the resource name, which is empty if the object doesn't exist.
+func (g *generator) generate(ctx context.Context, resourceType string, name string, importID string) (string, error) {
+	r := g.resources[resourceType]
+
+	d := r.Data(nil)
+	d.SetId(importID)
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		imported, err := r.Importer.StateContext(ctx, d, g.meta)
+		if err != nil {
+			return "", fmt.Errorf("failed to import %s %s: %w", resourceType, importID, err)
+		}
+		d = imported[0]
+	}
+	if diags := r.ReadContext(ctx, d, g.meta); diags.HasError() {
+		return "", fmt.Errorf("failed to read %s %s: %s", resourceType, importID, diags[0].Summary)
+	}
+	if d.Id() == "" {
+		log.Printf("[WARN] skipping %s %s, because it doesn't exist anymore", resourceType, importID)
+		return "", nil
+	}
+
+	name = g.uniqueName(name)
+	address := resourceType + "." + name
+	switch resourceType {
+	case "gitlab_group", "gitlab_project":
+		g.references[d.Id()] = address
+	}
+
+	block := g.config.Body().AppendNewBlock("resource", []string{resourceType, name})
+	g.writeAttributes(block.Body(), address, r.Schema, d.Get)
+	g.config.Body().AppendNewline()
+
+	g.imports = append(g.imports, generatedImport{address: address, id: importID})
+	return name, nil
+}
+
+var generatedNameInvalidCharsRegexp = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// uniqueName returns a valid and unused resource name for the given name, e.g. `foo_bar` for `Foo/bar`.
+func (g *generator) uniqueName(name string) string {
+	name = strings.Trim(generatedNameInvalidCharsRegexp.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// generatedReferenceAttributes are the attributes which may reference a generated group or project.
+var generatedReferenceAttributes = map[string]bool{
+	"group": true, "group_id": true, "project": true, "project_id": true, "namespace_id": true, "parent_id": true,
+}
+
+// writeAttributes writes the configurable attributes of the given schema which differ from their default
+// and returns how many have been written. Nested blocks without any such attribute are omitted.
+// The address of the resource or nested block is used to name the variables for sensitive attributes.
+func (g *generator) writeAttributes(body *hclwrite.Body, address string, s map[string]*schema.Schema, get func(string) interface{}) int {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	written := make(map[string]bool)
+	for _, k := range keys {
+		attribute := s[k]
+		if (!attribute.Required && !attribute.Optional) || attribute.Deprecated != "" {
+			continue
+		}
+		conflicts := false
+		for _, conflict := range attribute.ConflictsWith {
+			conflicts = conflicts || written[conflict]
+		}
+		if conflicts {
+			continue
+		}
+
+		value := get(k)
+		if set, ok := value.(*schema.Set); ok {
+			value = set.List()
+		}
+		if !attribute.Required && isGeneratedDefault(attribute, value) {
+			continue
+		}
+		written[k] = true
+
+		if reference, ok := g.references[fmt.Sprint(value)]; ok && generatedReferenceAttributes[k] {
+			body.SetAttributeTraversal(k, hcl.Traversal{
+				hcl.TraverseRoot{Name: strings.SplitN(reference, ".", 2)[0]},
+				hcl.TraverseAttr{Name: strings.SplitN(reference, ".", 2)[1]},
+				hcl.TraverseAttr{Name: "id"},
+			})
+			continue
+		}
+		if attribute.Sensitive {
+			body.SetAttributeTraversal(k, hcl.Traversal{
+				hcl.TraverseRoot{Name: "var"},
+				hcl.TraverseAttr{Name: g.variable(address+"."+k, attribute)},
+			})
+			continue
+		}
+
+		if nested, ok := attribute.Elem.(*schema.Resource); ok {
+			for _, element := range value.([]interface{}) {
+				values, _ := element.(map[string]interface{})
+				block := body.AppendNewBlock(k, nil)
+				if g.writeAttributes(block.Body(), address+"."+k, nested.Schema, func(k string) interface{} { return values[k] }) == 0 {
+					body.RemoveBlock(block)
+				}
+			}
+			continue
+		}
+		body.SetAttributeValue(k, generatedValue(attribute, value))
+	}
+	return len(written)
+}
+
+// variable adds a variable for the sensitive attribute with the given address and returns its name,
+// e.g. `foo_value` for `gitlab_project_variable.foo.value`.
+func (g *generator) variable(attribute string, s *schema.Schema) string {
+	name := strings.ReplaceAll(strings.SplitN(attribute, ".", 2)[1], ".", "_")
+	unique := name
+	for i := 2; g.variableNames[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	g.variableNames[unique] = true
+	g.variables = append(g.variables, generatedVariable{name: unique, attribute: attribute, schema: s})
+	return unique
+}
+
+// isGeneratedDefault checks if the given value is the default of the attribute, thus it doesn't need to be configured.
+func isGeneratedDefault(attribute *schema.Schema, value interface{}) bool {
+	if attribute.Default != nil {
+		return reflect.DeepEqual(attribute.Default, value)
+	}
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// generatedValue converts the given value of an attribute with a primitive or a collection of primitives type.
+func generatedValue(attribute *schema.Schema, value interface{}) cty.Value {
+	switch attribute.Type {
+	case schema.TypeBool:
+		b, _ := value.(bool)
+		return cty.BoolVal(b)
+	case schema.TypeInt:
+		i, _ := value.(int)
+		return cty.NumberIntVal(int64(i))
+	case schema.TypeFloat:
+		f, _ := value.(float64)
+		return cty.NumberFloatVal(f)
+	case schema.TypeList, schema.TypeSet:
+		elem, _ := attribute.Elem.(*schema.Schema)
+		if elem == nil {
+			elem = &schema.Schema{Type: schema.TypeString}
+		}
+		var values []cty.Value
+		for _, v := range value.([]interface{}) {
+			values = append(values, generatedValue(elem, v))
+		}
+		return cty.TupleVal(values)
+	case schema.TypeMap:
+		elem, _ := attribute.Elem.(*schema.Schema)
+		if elem == nil {
+			elem = &schema.Schema{Type: schema.TypeString}
+		}
+		values := make(map[string]cty.Value)
+		for k, v := range value.(map[string]interface{}) {
+			values[k] = generatedValue(elem, v)
+		}
+		return cty.ObjectVal(values)
+	}
+	return cty.StringVal(fmt.Sprint(value))
+}
+
+func (g *generator) variableBlocks() []byte {
+	file := hclwrite.NewEmptyFile()
+	for i, v := range g.variables {
+		if i > 0 {
+			file.Body().AppendNewline()
+		}
+		block := file.Body().AppendNewBlock("variable", []string{v.name})
+		block.Body().SetAttributeValue("description", cty.StringVal(fmt.Sprintf("The value of %s.", v.attribute)))
+		block.Body().SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: generatedVariableType(v.schema)}})
+		block.Body().SetAttributeValue("sensitive", cty.True)
+	}
+	return file.Bytes()
+}
+
+// generatedVariableType returns the type constraint of a variable for an attribute with a primitive type.
+func generatedVariableType(attribute *schema.Schema) string {
+	switch attribute.Type {
+	case schema.TypeBool:
+		return "bool"
+	case schema.TypeInt, schema.TypeFloat:
+		return "number"
+	case schema.TypeString:
+		return "string"
+	}
+	return "any"
+}
+
+func (g *generator) importBlocks() []byte {
+	file := hclwrite.NewEmptyFile()
+	for i, imp := range g.imports {
+		if i > 0 {
+			file.Body().AppendNewline()
+		}
+		block := file.Body().AppendNewBlock("import", nil)
+		block.Body().SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: strings.SplitN(imp.address, ".", 2)[0]},
+			hcl.TraverseAttr{Name: strings.SplitN(imp.address, ".", 2)[1]},
+		})
+		block.Body().SetAttributeValue("id", cty.StringVal(imp.id))
+	}
+	return file.Bytes()
+}
+
+func (g *generator) importScript() []byte {
+	var script strings.Builder
+	script.WriteString("#!/usr/bin/env sh\nset -e\n\n")
+	for _, imp := range g.imports {
+		fmt.Fprintf(&script, "terraform import %s '%s'\n", imp.address, strings.ReplaceAll(imp.id, "'", `'\''`))
+	}
+	return []byte(script.String())
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitlab_Generate(t *testing.T) {
+	f := newFakeGitlab(t)
+	client := testFakeGitlabMeta(t, f).client
+	userID := f.addUser("jane", false)
+
+	group, _, err := client.Groups.CreateGroup(&gitlab.CreateGroupOptions{Name: gitlab.String("ACME"), Path: gitlab.String("acme"), Description: gitlab.String("The ACME group")})
+	if err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	if _, _, err := client.Groups.CreateGroup(&gitlab.CreateGroupOptions{Name: gitlab.String("Sub"), Path: gitlab.String("sub"), ParentID: &group.ID}); err != nil {
+		t.Fatalf("failed to create subgroup: %v", err)
+	}
+	if _, _, err := client.GroupVariables.CreateVariable(group.ID, &gitlab.CreateGroupVariableOptions{Key: gitlab.String("TOKEN"), Value: gitlab.String("it's a secret"), EnvironmentScope: gitlab.String("prod")}); err != nil {
+		t.Fatalf("failed to create group variable: %v", err)
+	}
+	project, _, err := client.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: gitlab.String("App"), NamespaceID: &group.ID, InitializeWithReadme: gitlab.Bool(true)})
+	if err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	if _, _, err := client.ProjectMembers.AddProjectMember(project.ID, &gitlab.AddProjectMemberOptions{UserID: userID, AccessLevel: gitlab.AccessLevel(gitlab.DeveloperPermissions)}); err != nil {
+		t.Fatalf("failed to add project member: %v", err)
+	}
+	if _, _, err := client.Projects.AddProjectHook(project.ID, &gitlab.AddProjectHookOptions{URL: gitlab.String("https://example.com/hook"), PushEvents: gitlab.Bool(false), IssuesEvents: gitlab.Bool(true)}); err != nil {
+		t.Fatalf("failed to add project hook: %v", err)
+	}
+
+	config := Config{Token: fakeGitlabToken, BaseURL: f.BaseURL()}
+	dir := t.TempDir()
+	if err := Generate(context.Background(), GenerateOptions{Config: config, Group: "acme", Dir: dir}); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	generated, _ := os.ReadFile(filepath.Join(dir, "gitlab.tf"))
+	// The attributes are aligned by their longest name in a block, which is ignored for the comparison.
+	normalized := regexp.MustCompile(` +`).ReplaceAllString(string(generated), " ")
+	for _, want := range []string{
+		`resource "gitlab_group" "acme" {`,
+		` description = "The ACME group"`,
+		`resource "gitlab_group" "acme_sub" {`,
+		` parent_id = gitlab_group.acme.id`,
+		`resource "gitlab_group_variable" "acme_token_prod" {`,
+		` group = gitlab_group.acme.id`,
+		` value = var.acme_token_prod_value`,
+		`resource "gitlab_project" "acme_app" {`,
+		` namespace_id = gitlab_group.acme.id`,
+		`resource "gitlab_project_membership" "acme_app_jane" {`,
+		` access_level = "developer"`,
+		`resource "gitlab_project_hook" "acme_app_hook_`,
+		` issues_events = true`,
+		` push_events = false`,
+		`resource "gitlab_branch_protection" "acme_app_main" {`,
+		` branch = "main"`,
+	} {
+		if !strings.Contains(normalized, want) {
+			t.Fatalf("expected the generated configuration to contain %q:\n%s", want, generated)
+		}
+	}
+
+	if strings.Contains(normalized, "it's a secret") {
+		t.Fatalf("expected no sensitive values in the generated configuration:\n%s", generated)
+	}
+	variables, _ := os.ReadFile(filepath.Join(dir, "variables.tf"))
+	normalizedVariables := regexp.MustCompile(` +`).ReplaceAllString(string(variables), " ")
+	for _, want := range []string{`variable "acme_token_prod_value" {`, ` type = string`, ` sensitive = true`} {
+		if !strings.Contains(normalizedVariables, want) {
+			t.Fatalf("expected the generated variables to contain %q:\n%s", want, variables)
+		}
+	}
+
+	if strings.Contains(normalized, "push_rules {") {
+		t.Fatalf("expected no nested blocks without configured attributes:\n%s", generated)
+	}
+
+	imports, _ := os.ReadFile(filepath.Join(dir, "imports.tf"))
+	wantImport := "import {\n  to = gitlab_group_variable.acme_token_prod\n  id = \"" + f.path("groups", "acme")[len("groups/"):] + ":TOKEN:prod\"\n}\n"
+	if !strings.Contains(string(imports), wantImport) {
+		t.Fatalf("expected the imports to contain %q:\n%s", wantImport, imports)
+	}
+
+	if err := Generate(context.Background(), GenerateOptions{Config: config, Group: "acme", Dir: dir}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected an error for existing files, got %v", err)
+	}
+
+	scriptDir := t.TempDir()
+	if err := Generate(context.Background(), GenerateOptions{Config: config, Group: "acme/sub", Dir: scriptDir, ImportScript: true}); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	script, _ := os.ReadFile(filepath.Join(scriptDir, "import.sh"))
+	if want := "terraform import gitlab_group.acme_sub '"; !strings.Contains(string(script), want) {
+		t.Fatalf("expected the import script to contain %q:\n%s", want, script)
+	}
+}
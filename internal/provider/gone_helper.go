@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/xanzy/go-gitlab"
+)
+
+// errGone is used by reads which look up the object of the resource in a list, to report that it isn't listed anymore.
+var errGone = errors.New("not found")
+
+// removeGoneFromState is called by the read function of every resource with the object it read and the error of the
+// request. If the object doesn't exist anymore, see isGone, or it's marked for deletion, see isMarkedForDeletion, it
+// removes the resource from the state with `d.SetId("")` and returns true, so that the read returns no error and
+// Terraform plans to create the object again. This also applies to objects which belong to a parent that doesn't
+// exist anymore, e.g. a variable of a deleted project. 404 responses of other requests, e.g. for optional settings,
+// aren't passed to it. The project and group resources pass no object, to keep an object marked for deletion in the
+// state and restore it, see customizeDiffRestoreMarkedForDeletion.
+func removeGoneFromState(ctx context.Context, d *schema.ResourceData, object interface{}, err error) bool {
+	reason := "marked for deletion"
+	if err != nil {
+		if !isGone(err) {
+			return false
+		}
+		reason = err.Error()
+	} else if !isMarkedForDeletion(object) {
+		return false
+	}
+
+	log.Printf("[DEBUG] %s %s is gone, removing it from the state: %s", resourceTypeFromContext(ctx), d.Id(), reason)
+	d.SetId("")
+	return true
+}
+
+// isGone checks if the given error is an API response which means that the object doesn't exist (anymore),
+// or errGone.
+func isGone(err error) bool {
+	if errors.Is(err, errGone) {
+		return true
+	}
+	errResponse, ok := err.(*gitlab.ErrorResponse)
+	return ok && errResponse.Response != nil &&
+		(errResponse.Response.StatusCode == http.StatusNotFound || errResponse.Response.StatusCode == http.StatusGone)
+}
+
+// isMarkedForDeletion checks if the given project or group is pending its delayed deletion.
+func isMarkedForDeletion(object interface{}) bool {
+	switch object := object.(type) {
+	case *gitlab.Project:
+		return object != nil && object.MarkedForDeletionAt != nil
+	case *gitlab.Group:
+		return object != nil && object.MarkedForDeletionOn != nil
+	}
+	return false
+}
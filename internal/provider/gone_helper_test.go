@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitlab_isMarkedForDeletion(t *testing.T) {
+	markedAt := gitlab.ISOTime{}
+	cases := map[string]struct {
+		Object interface{}
+		Want   bool
+	}{
+		"project":                     {Object: &gitlab.Project{}, Want: false},
+		"project marked for deletion": {Object: &gitlab.Project{MarkedForDeletionAt: &markedAt}, Want: true},
+		"group":                       {Object: &gitlab.Group{}, Want: false},
+		"group marked for deletion":   {Object: &gitlab.Group{MarkedForDeletionOn: &markedAt}, Want: true},
+		"other":                       {Object: &gitlab.User{}, Want: false},
+	}
+
+	for name, tc := range cases {
+		if got := isMarkedForDeletion(tc.Object); got != tc.Want {
+			t.Fatalf("%s: got %t expected %t", name, got, tc.Want)
+		}
+	}
+}
+
+func TestGitlab_removeGoneFromState(t *testing.T) {
+	markedAt := gitlab.ISOTime{}
+	errorResponse := func(statusCode int) error {
+		request := httptest.NewRequest(http.MethodGet, "/api/v4/projects/42", nil)
+		return &gitlab.ErrorResponse{Response: &http.Response{StatusCode: statusCode, Request: request}}
+	}
+	cases := map[string]struct {
+		Object interface{}
+		Err    error
+		Want   bool
+	}{
+		"read":                        {Object: &gitlab.Project{}, Want: false},
+		"not found":                   {Err: errorResponse(http.StatusNotFound), Want: true},
+		"gone":                        {Err: errorResponse(http.StatusGone), Want: true},
+		"not listed":                  {Err: errGone, Want: true},
+		"wrapped not listed":          {Err: fmt.Errorf("token %w", errGone), Want: true},
+		"forbidden":                   {Err: errorResponse(http.StatusForbidden), Want: false},
+		"other error":                 {Err: errors.New("connection refused"), Want: false},
+		"project marked for deletion": {Object: &gitlab.Project{MarkedForDeletionAt: &markedAt}, Want: true},
+		"group marked for deletion":   {Object: &gitlab.Group{MarkedForDeletionOn: &markedAt}, Want: true},
+		"nil project":                 {Object: (*gitlab.Project)(nil), Err: errorResponse(http.StatusInternalServerError), Want: false},
+	}
+
+	for name, tc := range cases {
+		d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+		d.SetId("42")
+		if got := removeGoneFromState(context.Background(), d, tc.Object, tc.Err); got != tc.Want {
+			t.Fatalf("%s: got %t expected %t", name, got, tc.Want)
+		}
+		if removed := d.Id() == ""; removed != tc.Want {
+			t.Fatalf("%s: removed from state %t expected %t", name, removed, tc.Want)
+		}
+	}
+}
@@ -1,26 +1,173 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/xanzy/go-gitlab"
 )
 
 // Helper method for modifying client requests appropriately for sending a GraphQL call instead of a REST call.
+// GraphQL errors in the response are returned as GraphQLErrors, use diagnosticsFromGraphQLError to surface them.
 func SendGraphQLRequest(ctx context.Context, client *gitlab.Client, query GraphQLQuery, response interface{}) (interface{}, error) {
-	request, err := client.NewRequest("POST", "", query, nil)
+	request, err := client.NewRequest("POST", "", query, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
 	if err != nil {
 		return nil, err
 	}
 	// Overwrite the path of the existing request, as otherwise the go-gitlab client appends /api/v4 instead.
-	request.URL.Path = "/api/graphql"
-	if _, err = client.Do(request, response); err != nil {
+	request.URL.Path = graphQLPath(client.BaseURL().Path)
+	request.URL.RawPath = ""
+
+	var body bytes.Buffer
+	if _, err = client.Do(request, &body); err != nil {
 		return nil, err
 	}
+
+	var envelope struct {
+		Errors GraphQLErrors `json:"errors"`
+	}
+	if err := json.Unmarshal(body.Bytes(), &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return nil, envelope.Errors
+	}
+
+	if err := json.Unmarshal(body.Bytes(), response); err != nil {
+		return nil, fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
 	return response, nil
 }
 
+// SendPaginatedGraphQLRequest sends the given GraphQL query once for every page of the connection at connectionPath,
+// e.g. `project.members`, and calls handlePage with the `data` of each response.
+// The query must request the `pageInfo { hasNextPage endCursor }` of the connection and pass the `$after` variable
+// as the `after` argument of the connection, which is set to the `endCursor` of the previous page.
+func SendPaginatedGraphQLRequest(ctx context.Context, client *gitlab.Client, query GraphQLQuery, connectionPath string, handlePage func(data json.RawMessage) error) error {
+	variables := make(map[string]interface{}, len(query.Variables)+1)
+	for k, v := range query.Variables {
+		variables[k] = v
+	}
+	query.Variables = variables
+
+	for {
+		var response struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if _, err := SendGraphQLRequest(ctx, client, query, &response); err != nil {
+			return err
+		}
+		if err := handlePage(response.Data); err != nil {
+			return err
+		}
+
+		pageInfo, err := graphQLPageInfo(response.Data, connectionPath)
+		if err != nil {
+			return err
+		}
+		if !pageInfo.HasNextPage {
+			return nil
+		}
+		if pageInfo.EndCursor == "" || pageInfo.EndCursor == variables["after"] {
+			return fmt.Errorf("GraphQL connection %q has a next page, but no new end cursor", connectionPath)
+		}
+		variables["after"] = pageInfo.EndCursor
+	}
+}
+
 // Represents a GraphQL call to the API. All GraphQL calls are a string passed to the "query" parameter, so they should be included here.
+// Values for the variables declared by the query are passed in Variables, e.g. `{"fullPath": "foo/bar"}` for `query($fullPath: ID!)`.
 type GraphQLQuery struct {
-	Query string `json:"query"`
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLError represents a single entry of the `errors` array of a GraphQL response.
+type GraphQLError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+func (e GraphQLError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	path := make([]string, len(e.Path))
+	for i, p := range e.Path {
+		path[i] = fmt.Sprint(p)
+	}
+	return fmt.Sprintf("%s (path: %s)", e.Message, strings.Join(path, "."))
+}
+
+// GraphQLErrors represents the `errors` array of a GraphQL response.
+// GitLab responds with `200 OK` even if the query fails, thus it's returned as an error by SendGraphQLRequest.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "GraphQL request failed: " + strings.Join(messages, "; ")
+}
+
+// diagnosticsFromGraphQLError converts the given error to diagnostics, with one diagnostic for every GraphQL error.
+func diagnosticsFromGraphQLError(err error) diag.Diagnostics {
+	graphQLErrors, ok := err.(GraphQLErrors)
+	if !ok {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	for _, e := range graphQLErrors {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "GraphQL request failed",
+			Detail:   e.Error(),
+		})
+	}
+	return diags
+}
+
+type graphQLPageInfoResponse struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// graphQLPageInfo extracts the `pageInfo` of the connection at the given dot separated path from the response data.
+func graphQLPageInfo(data json.RawMessage, connectionPath string) (*graphQLPageInfoResponse, error) {
+	current := data
+	for _, field := range strings.Split(connectionPath, ".") {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(current, &object); err != nil {
+			return nil, fmt.Errorf("GraphQL response has no object at %q", connectionPath)
+		}
+		// An object may be null, e.g. if the requested project doesn't exist, in which case there are no more pages.
+		if object == nil {
+			return &graphQLPageInfoResponse{}, nil
+		}
+		current = object[field]
+	}
+	if string(current) == "null" {
+		return &graphQLPageInfoResponse{}, nil
+	}
+
+	var connection struct {
+		PageInfo *graphQLPageInfoResponse `json:"pageInfo"`
+	}
+	if err := json.Unmarshal(current, &connection); err != nil || connection.PageInfo == nil {
+		return nil, fmt.Errorf("GraphQL response has no pageInfo at %q", connectionPath)
+	}
+	return connection.PageInfo, nil
+}
+
+// graphQLPath returns the path of the GraphQL API for the given path of the REST API base URL,
+// which keeps the relative URL root of GitLab, e.g. `/gitlab/api/v4/` -> `/gitlab/api/graphql`.
+func graphQLPath(baseURLPath string) string {
+	root := strings.TrimSuffix(strings.TrimSuffix(baseURLPath, "/"), "/api/v4")
+	return root + "/api/graphql"
 }
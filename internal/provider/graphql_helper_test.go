@@ -1,28 +1,138 @@
-//go:build acceptance
-// +build acceptance
-
 package provider
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/xanzy/go-gitlab"
 )
 
-func TestAcc_GraphQL_basic(t *testing.T) {
+func TestGitlab_graphQLPath(t *testing.T) {
+	cases := map[string]string{
+		"/api/v4/":        "/api/graphql",
+		"/api/v4":         "/api/graphql",
+		"/gitlab/api/v4/": "/gitlab/api/graphql",
+	}
 
-	query := GraphQLQuery{
-		`query {currentUser {name, bot, gitpodEnabled, groupCount, id, namespace{id}, publicEmail, username}}`,
+	for baseURLPath, expected := range cases {
+		if got := graphQLPath(baseURLPath); got != expected {
+			t.Fatalf("%s: got %q expected %q", baseURLPath, got, expected)
+		}
 	}
+}
+
+func TestGitlab_SendGraphQLRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gitlab/api/graphql" {
+			http.NotFound(w, r)
+			return
+		}
 
-	var response CurrentUserResponse
-	_, err := SendGraphQLRequest(context.Background(), testGitlabClient, query, &response)
+		var query GraphQLQuery
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			t.Fatalf("failed to decode query: %v", err)
+		}
+
+		switch query.Variables["fullPath"] {
+		case "foo/bar":
+			pages := map[interface{}]string{
+				nil:  `{"data": {"project": {"members": {"nodes": [{"id": "1"}, {"id": "2"}], "pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}}`,
+				"c1": `{"data": {"project": {"members": {"nodes": [{"id": "3"}], "pageInfo": {"hasNextPage": false, "endCursor": "c2"}}}}}`,
+			}
+			w.Write([]byte(pages[query.Variables["after"]]))
+		case "foo/missing":
+			w.Write([]byte(`{"data": {"project": null}}`))
+		default:
+			w.Write([]byte(`{"data": null, "errors": [{"message": "Variable $fullPath of type ID! was provided invalid value", "path": ["project", 0]}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewOAuthClient("token", gitlab.WithBaseURL(server.URL+"/gitlab/api/v4"))
 	if err != nil {
-		log.Println(err)
-		t.Fail()
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	query := GraphQLQuery{
+		Query: `query($fullPath: ID!, $after: String) { project(fullPath: $fullPath) { members(after: $after) { nodes { id } pageInfo { hasNextPage endCursor } } } }`,
+	}
+
+	cases := []struct {
+		FullPath  string
+		WantIDs   string
+		WantError string
+	}{
+		{FullPath: "foo/bar", WantIDs: "1,2,3"},
+		{FullPath: "foo/missing", WantIDs: ""},
+		{FullPath: "", WantError: "GraphQL request failed: Variable $fullPath of type ID! was provided invalid value (path: project.0)"},
+	}
+
+	for _, tc := range cases {
+		query.Variables = map[string]interface{}{"fullPath": tc.FullPath}
+
+		var ids []string
+		err := SendPaginatedGraphQLRequest(context.Background(), client, query, "project.members", func(data json.RawMessage) error {
+			var page struct {
+				Project *struct {
+					Members struct {
+						Nodes []struct {
+							ID string `json:"id"`
+						} `json:"nodes"`
+					} `json:"members"`
+				} `json:"project"`
+			}
+			if err := json.Unmarshal(data, &page); err != nil {
+				return err
+			}
+			if page.Project != nil {
+				for _, node := range page.Project.Members.Nodes {
+					ids = append(ids, node.ID)
+				}
+			}
+			return nil
+		})
+
+		if tc.WantError != "" {
+			if err == nil || err.Error() != tc.WantError {
+				t.Fatalf("%q: got error %v expected %q", tc.FullPath, err, tc.WantError)
+			}
+			if diags := diagnosticsFromGraphQLError(err); len(diags) != 1 || !strings.Contains(diags[0].Detail, "invalid value") {
+				t.Fatalf("%q: got diagnostics %v", tc.FullPath, diags)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.FullPath, err)
+		}
+		if got := strings.Join(ids, ","); got != tc.WantIDs {
+			t.Fatalf("%q: got ids %q expected %q", tc.FullPath, got, tc.WantIDs)
+		}
+		if _, ok := query.Variables["after"]; ok {
+			t.Fatalf("%q: the variables of the given query must not be modified", tc.FullPath)
+		}
+	}
+}
+
+func TestGitlab_graphQLQueryDataSourceValidation(t *testing.T) {
+	validate := New("dev")().DataSourcesMap["gitlab_graphql_query"].Schema["query"].ValidateFunc
+
+	cases := map[string]bool{
+		`query { currentUser { name } }`:                                   true,
+		`{ project(fullPath: "#") { id } }`:                                true,
+		`query { project(fullPath: "}") { id } }`:                          true,
+		`mutation { destroy { id } }`:                                      false,
+		`query { p(x: "#") { id } } mutation { destroy { id } }`:           false,
+		`query { p(x: """ } """) { id } } subscription { updated { id } }`: false,
+		`query { p(x: "unterminated) { id } }`:                             false,
 	}
 
-	if response.Data.CurrentUser.Name != "Administrator" {
-		t.Fail()
+	for query, valid := range cases {
+		if _, errs := validate(query, "query"); (len(errs) == 0) != valid {
+			t.Fatalf("%s: got errors %v expected valid to be %v", query, errs, valid)
+		}
 	}
 }
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/xanzy/go-gitlab"
+)
+
+// providerMeta is the meta value passed to all resources and data sources of a configured provider.
+//
+// Besides the GitLab client it carries state which is shared by all resources and data sources
+// during a Terraform run. Values which are expensive to retrieve but don't change during a run
+// are fetched lazily and cached once they have been retrieved successfully,
+// thus the meta must always be passed as a pointer.
+//
+// Resources retrieve the client with `client := meta.(*providerMeta).client`.
+type providerMeta struct {
+	client *gitlab.Client
+
+	// locks serializes API calls which conflict with each other when made concurrently
+	// for the same object, e.g. commits to the same branch. See lockKey for the used keys.
+	locks *keyedLock
+
+	// serverInfo is the cached version and edition of the GitLab instance, see getServerInfo.
+	serverInfoMu sync.Mutex
+	serverInfo   *gitlabServerInfo
+
+	// currentUser is the cached user the provider is authenticated as, see getCurrentUser.
+	currentUserMu sync.Mutex
+	currentUser   *gitlab.User
+}
+
+func newProviderMeta(client *gitlab.Client) *providerMeta {
+	return &providerMeta{client: client, locks: newKeyedLock()}
+}
+
+// lockKey builds a key for the providerMeta locks from the given parts,
+// e.g. `lockKey("project", "42", "branch", "main")`.
+//
+// The parts are used as configured, i.e. they aren't resolved to the IDs of the objects.
+// Thus, the same project referenced by ID in one resource and by its path in another one
+// is locked with different keys, and calls for it aren't serialized between those resources.
+func lockKey(parts ...string) string {
+	return strings.Join(parts, ":")
+}
+
+// getCurrentUser returns the user the provider is authenticated as.
+// The user is fetched on first use and then served from the cache. Like for getServerInfo,
+// a failed fetch isn't cached.
+func (m *providerMeta) getCurrentUser(ctx context.Context) (*gitlab.User, error) {
+	m.currentUserMu.Lock()
+	defer m.currentUserMu.Unlock()
+
+	if m.currentUser == nil {
+		currentUser, _, err := m.client.Users.CurrentUser(gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		m.currentUser = currentUser
+	}
+	return m.currentUser, nil
+}
+
+// isCurrentUserAdmin checks if the user the provider is authenticated as is an instance administrator.
+func (m *providerMeta) isCurrentUserAdmin(ctx context.Context) (bool, error) {
+	currentUser, err := m.getCurrentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return currentUser.IsAdmin, nil
+}
+
+// gitlabServerInfo describes the version and edition of the GitLab instance the provider is connected to.
+type gitlabServerInfo struct {
+	Version    string
+	Revision   string
+	Enterprise bool
+}
+
+func (i *gitlabServerInfo) String() string {
+	edition := "CE"
+	if i.Enterprise {
+		edition = "EE"
+	}
+	return fmt.Sprintf("GitLab %s %s", i.Version, edition)
+}
+
+// gitlabMetadata is the response of the `/metadata` API, which is available since GitLab 15.2.
+// see https://docs.gitlab.com/ee/api/metadata.html
+type gitlabMetadata struct {
+	Version    string `json:"version"`
+	Revision   string `json:"revision"`
+	Enterprise *bool  `json:"enterprise"`
+}
+
+// getServerInfo returns the version and edition of the GitLab instance.
+// They are fetched on first use and then served from the cache. A failed fetch isn't cached,
+// thus it's retried by the next caller, e.g. after a temporary network error.
+func (m *providerMeta) getServerInfo(ctx context.Context) (*gitlabServerInfo, error) {
+	m.serverInfoMu.Lock()
+	defer m.serverInfoMu.Unlock()
+
+	if m.serverInfo == nil {
+		info, err := fetchGitlabServerInfo(ctx, m.client)
+		if err != nil {
+			return nil, err
+		}
+		m.serverInfo = info
+	}
+	return m.serverInfo, nil
+}
+
+func fetchGitlabServerInfo(ctx context.Context, client *gitlab.Client) (*gitlabServerInfo, error) {
+	version, _, err := client.Version.GetVersion(gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitLab version: %w", err)
+	}
+
+	// The version of an Enterprise Edition instance has an `-ee` suffix, e.g. `15.3.0-ee`.
+	info := &gitlabServerInfo{
+		Version:    version.Version,
+		Revision:   version.Revision,
+		Enterprise: strings.HasSuffix(version.Version, "-ee"),
+	}
+
+	// Newer GitLab versions report the edition explicitly in the metadata.
+	req, err := client.NewRequest(http.MethodGet, "metadata", nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	metadata := new(gitlabMetadata)
+	if _, err := client.Do(req, metadata); err != nil {
+		if !is404(err) {
+			return nil, fmt.Errorf("failed to get GitLab metadata: %w", err)
+		}
+	} else if metadata.Enterprise != nil {
+		info.Enterprise = *metadata.Enterprise
+	}
+
+	return info, nil
+}
+
+// isAtLeast checks that the version of GitLab is at least the provided wantVersion and, if
+// requested, that it's an Enterprise Edition. It only checks the major and minor version numbers, not the patch.
+func (i *gitlabServerInfo) isAtLeast(wantVersion string, wantEnterprise bool) (bool, error) {
+	if wantEnterprise && !i.Enterprise {
+		return false, nil
+	}
+	if wantVersion == "" {
+		return true, nil
+	}
+	return isVersionAtLeast(i.Version, wantVersion)
+}
+
+// customizeDiffRequiresGitLab returns a CustomizeDiffFunc which fails the plan with a descriptive
+// error if the given attribute is configured but the GitLab instance doesn't have at least
+// the given version (e.g. `15.3`, may be empty) or isn't an Enterprise Edition, if requested.
+//
+// This gives a clear error message at plan time, instead of an opaque 400 or 404 during apply.
+// If the version cannot be determined, e.g. because the token isn't allowed to read it, the check is skipped.
+func customizeDiffRequiresGitLab(attribute string, minVersion string, enterprise bool) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !isAttributeConfigured(d, attribute) {
+			return nil
+		}
+
+		info, err := meta.(*providerMeta).getServerInfo(ctx)
+		if err != nil {
+			return nil
+		}
+
+		ok, err := info.isAtLeast(minVersion, enterprise)
+		if err != nil || ok {
+			return err
+		}
+
+		requirement := "GitLab"
+		if minVersion != "" {
+			requirement += " >= " + minVersion
+		}
+		if enterprise {
+			requirement += " EE"
+		}
+		return fmt.Errorf("attribute %q requires %s, but the instance runs %s", attribute, requirement, info)
+	}
+}
+
+// isAttributeConfigured checks if the top-level attribute is set in the configuration to a value
+// other than `false` or an empty collection. Unknown values are treated as not configured.
+func isAttributeConfigured(d *schema.ResourceDiff, attribute string) bool {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return false
+	}
+
+	value := rawConfig.GetAttr(attribute)
+	switch {
+	case value.IsNull() || !value.IsKnown():
+		return false
+	case value.Type() == cty.Bool:
+		return value.True()
+	case value.Type().IsCollectionType() || value.Type().IsTupleType():
+		return value.LengthInt() > 0
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitlab_fetchGitlabServerInfo(t *testing.T) {
+	cases := []struct {
+		Version        string
+		Metadata       string
+		WantEnterprise bool
+	}{
+		{
+			Version:        "15.1.0-ee",
+			WantEnterprise: true,
+		},
+		{
+			Version:        "15.1.0",
+			WantEnterprise: false,
+		},
+		{
+			Version:        "15.6.0",
+			Metadata:       `{"version": "15.6.0", "enterprise": true}`,
+			WantEnterprise: true,
+		},
+	}
+
+	for _, tc := range cases {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v4/version", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"version": "` + tc.Version + `", "revision": "abc"}`))
+		})
+		mux.HandleFunc("/api/v4/metadata", func(w http.ResponseWriter, r *http.Request) {
+			if tc.Metadata == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(tc.Metadata))
+		})
+		server := httptest.NewServer(mux)
+
+		client, err := gitlab.NewOAuthClient("token", gitlab.WithBaseURL(server.URL))
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		info, err := fetchGitlabServerInfo(context.Background(), client)
+		server.Close()
+		if err != nil {
+			t.Fatalf("unexpected error for version %q: %v", tc.Version, err)
+		}
+		if info.Version != tc.Version || info.Enterprise != tc.WantEnterprise {
+			t.Fatalf("got %s expected version %q with enterprise %v", info, tc.Version, tc.WantEnterprise)
+		}
+	}
+}
+
+func TestGitlab_gitlabServerInfo_isAtLeast(t *testing.T) {
+	cases := []struct {
+		Info           gitlabServerInfo
+		WantVersion    string
+		WantEnterprise bool
+		Expected       bool
+	}{
+		{Info: gitlabServerInfo{Version: "15.3.0-ee", Enterprise: true}, WantVersion: "15.3", WantEnterprise: true, Expected: true},
+		{Info: gitlabServerInfo{Version: "15.3.0", Enterprise: false}, WantVersion: "15.3", WantEnterprise: true, Expected: false},
+		{Info: gitlabServerInfo{Version: "15.2.1", Enterprise: false}, WantVersion: "15.3", WantEnterprise: false, Expected: false},
+		{Info: gitlabServerInfo{Version: "16.0.0", Enterprise: false}, WantVersion: "15.3", WantEnterprise: false, Expected: true},
+		{Info: gitlabServerInfo{Version: "14.10.0-ee", Enterprise: true}, WantVersion: "", WantEnterprise: true, Expected: true},
+	}
+
+	for _, tc := range cases {
+		ok, err := tc.Info.isAtLeast(tc.WantVersion, tc.WantEnterprise)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", &tc.Info, err)
+		}
+		if ok != tc.Expected {
+			t.Fatalf("got %v expected %v for %s requiring %q (enterprise: %v)", ok, tc.Expected, &tc.Info, tc.WantVersion, tc.WantEnterprise)
+		}
+	}
+}
+
+func TestGitlab_providerMeta_getServerInfo(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/version":
+			requests++
+			if requests == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"message": "401 Unauthorized"}`))
+				return
+			}
+			w.Write([]byte(`{"version": "15.3.0-ee", "revision": "abc"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := gitlab.NewOAuthClient("token", gitlab.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	meta := newProviderMeta(client)
+
+	// A failed fetch is not cached, but a successful one is.
+	if _, err := meta.getServerInfo(context.Background()); err == nil {
+		t.Fatalf("expected the first fetch to fail")
+	}
+	for i := 0; i < 2; i++ {
+		info, err := meta.getServerInfo(context.Background())
+		if err != nil || info.Version != "15.3.0-ee" {
+			t.Fatalf("got %v and error %v expected version 15.3.0-ee", info, err)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("got %d version requests expected 2", requests)
+	}
+}
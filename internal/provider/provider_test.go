@@ -5,7 +5,10 @@ package provider
 
 import (
 	"context"
+	"math/rand"
+	"net/http"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -34,6 +37,23 @@ var testGitlabConfig = Config{
 var testGitlabClient *gitlab.Client
 
 func init() {
+	// The interactions of the test client and of the providers created by the providerFactories
+	// are recorded into, or replayed from, the cassette file, see newCassetteTransport.
+	if mode := os.Getenv("GITLAB_CASSETTE_MODE"); mode != "" {
+		file := os.Getenv("GITLAB_CASSETTE_FILE")
+		wrapBaseTransport = func(transport http.RoundTripper) (http.RoundTripper, error) {
+			return newCassetteTransport(transport, mode, file)
+		}
+
+		// The names generated by acctest are part of the recorded URLs, thus they must be the same when replaying.
+		// acctest uses the global random source, which it seeds with the current time in its own init.
+		seed, err := strconv.ParseInt(os.Getenv("GITLAB_CASSETTE_SEED"), 10, 64)
+		if err != nil {
+			seed = 1
+		}
+		rand.Seed(seed)
+	}
+
 	client, err := testGitlabConfig.Client(context.Background())
 	if err != nil {
 		panic("failed to create test client: " + err.Error()) // lintignore: R009 // TODO: Resolve this tfproviderlint issue
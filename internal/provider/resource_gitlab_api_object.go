@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var _ = registerResource("gitlab_api_object", func() *schema.Resource {
+	return &schema.Resource{
+		Description: `The ` + "`gitlab_api_object`" + ` resource allows to manage an object of an arbitrary endpoint of the GitLab REST API.
+
+It's meant to manage objects which aren't supported by any other resource yet. The requests are sent with the authentication and settings of the provider.
+The paths are relative to the ` + "`base_url`" + ` of the provider and may contain an ` + "`{id}`" + ` placeholder, which is replaced with the ID of the object.
+
+-> Changes to the object made outside of Terraform are only detected for the keys listed in ` + "`drift_detection_keys`" + `.
+
+-> This resource doesn't support import, because the paths of the object can't be derived from its ID. An existing object must be recreated with this resource, or managed with a resource made for its kind.
+
+**Upstream API**: [GitLab REST API docs](https://docs.gitlab.com/ee/api/api_resources.html)`,
+
+		CreateContext: resourceGitlabAPIObjectCreate,
+		ReadContext:   resourceGitlabAPIObjectRead,
+		UpdateContext: resourceGitlabAPIObjectUpdate,
+		DeleteContext: resourceGitlabAPIObjectDelete,
+
+		Schema: map[string]*schema.Schema{
+			"create_path": {
+				Description:  "The path the object is created with, e.g. `projects/42/hooks`. Changing it recreates the object.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"create_method": {
+				Description:  fmt.Sprintf("The HTTP method the object is created with. Changing it recreates the object. Valid values are: %s.", renderValueListForDocs([]string{"POST", "PUT"})),
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "POST",
+				ValidateFunc: validation.StringInSlice([]string{"POST", "PUT"}, false),
+			},
+			"read_path": {
+				Description:  "The path the object is read from, e.g. `projects/42/hooks/{id}`. Changing it recreates the object.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"update_path": {
+				Description: "The path the object is updated with. Defaults to `read_path`. Changing it only affects the following update requests.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"update_method": {
+				Description:  fmt.Sprintf("The HTTP method the object is updated with. Changing it only affects the following update requests. Valid values are: %s.", renderValueListForDocs([]string{"PUT", "POST", "PATCH"})),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "PUT",
+				ValidateFunc: validation.StringInSlice([]string{"PUT", "POST", "PATCH"}, false),
+			},
+			"delete_path": {
+				Description: "The path the object is deleted with. Defaults to `read_path`. Changing it only affects the following delete request.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"id_attribute": {
+				Description: "The dot separated path of the object ID in the response of the create request, e.g. `id` or `project.id`. Changing it recreates the object.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "id",
+			},
+			"body": {
+				Description:      "The JSON body sent with the create and update requests, e.g. `jsonencode({ url = \"https://example.com/hook\" })`.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				StateFunc: func(v interface{}) string {
+					body, _ := structure.NormalizeJsonString(v)
+					return body
+				},
+			},
+			"drift_detection_keys": {
+				Description: "The top-level keys of the `body` which are compared with the object read from `read_path` to detect changes made outside of Terraform.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"response_body": {
+				Description: "The body of the last read response as a JSON string. Use `jsondecode` to access its fields.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+})
+
+func resourceGitlabAPIObjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	path := d.Get("create_path").(string)
+	method := d.Get("create_method").(string)
+	log.Printf("[DEBUG] create GitLab API object with %s %s", method, path)
+	body, _, err := sendAPIRequest(ctx, client, method, path, nil, json.RawMessage(d.Get("body").(string)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	id, err := apiObjectID(body, d.Get("id_attribute").(string))
+	if err != nil {
+		return diag.Errorf("failed to get the ID of the created object from %s %s: %v", method, path, err)
+	}
+
+	d.SetId(id)
+	return resourceGitlabAPIObjectRead(ctx, d, meta)
+}
+
+func resourceGitlabAPIObjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	path := expandAPIPath(d.Get("read_path").(string), d.Id())
+	log.Printf("[DEBUG] read GitLab API object %s", path)
+	body, _, err := sendAPIRequest(ctx, client, "GET", path, nil, nil)
+	if removeGoneFromState(ctx, d, nil, err) {
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if keys := d.Get("drift_detection_keys").(*schema.Set); keys.Len() > 0 {
+		driftedBody, err := apiObjectDriftedBody(d.Get("body").(string), body, *stringSetToStringSlice(keys))
+		if err != nil {
+			return diag.Errorf("failed to detect changes of the GitLab API object %s: %v", path, err)
+		}
+		d.Set("body", driftedBody)
+	}
+
+	d.Set("response_body", string(body))
+	return nil
+}
+
+func resourceGitlabAPIObjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	// The object itself is only modified if its body changes. The other in-place changes, i.e. the paths and methods
+	// of the update and delete requests, only apply to the following requests, thus they just have to be stored in the state.
+	if d.HasChange("body") {
+		path := d.Get("update_path").(string)
+		if path == "" {
+			path = d.Get("read_path").(string)
+		}
+		path = expandAPIPath(path, d.Id())
+		method := d.Get("update_method").(string)
+
+		log.Printf("[DEBUG] update GitLab API object with %s %s", method, path)
+		if _, _, err := sendAPIRequest(ctx, client, method, path, nil, json.RawMessage(d.Get("body").(string))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceGitlabAPIObjectRead(ctx, d, meta)
+}
+
+func resourceGitlabAPIObjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	path := d.Get("delete_path").(string)
+	if path == "" {
+		path = d.Get("read_path").(string)
+	}
+	path = expandAPIPath(path, d.Id())
+
+	log.Printf("[DEBUG] delete GitLab API object %s", path)
+	if _, _, err := sendAPIRequest(ctx, client, "DELETE", path, nil, nil); err != nil && !is404(err) {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// apiObjectID returns the value at the given dot separated path of the JSON object in body as a string.
+func apiObjectID(body []byte, idAttribute string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+	for _, field := range strings.Split(idAttribute, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("the response has no object at %q", idAttribute)
+		}
+		value = object[field]
+	}
+
+	switch id := value.(type) {
+	case string:
+		if id != "" {
+			return id, nil
+		}
+	case json.Number:
+		return id.String(), nil
+	}
+	return "", fmt.Errorf("the response has no string or number at %q", idAttribute)
+}
+
+// apiObjectDriftedBody returns the given body with the values of the given keys replaced by the ones of the response,
+// so that Terraform shows a diff if they have been changed outside of Terraform.
+func apiObjectDriftedBody(body string, response []byte, keys []string) (string, error) {
+	var bodyObject, responseObject map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &bodyObject); err != nil {
+		return "", fmt.Errorf("the body is not a JSON object: %w", err)
+	}
+	if err := json.Unmarshal(response, &responseObject); err != nil {
+		return "", fmt.Errorf("the response is not a JSON object: %w", err)
+	}
+
+	for _, key := range keys {
+		if value, ok := responseObject[key]; ok {
+			bodyObject[key] = value
+		}
+	}
+
+	drifted, err := json.Marshal(bodyObject)
+	if err != nil {
+		return "", err
+	}
+	return string(drifted), nil
+}
@@ -0,0 +1,112 @@
+//go:build acceptance
+// +build acceptance
+
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestAccGitlabAPIObject_basic(t *testing.T) {
+	testProject := testAccCreateProject(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckGitlabAPIObjectDestroy(testProject.ID),
+		Steps: []resource.TestStep{
+			// Create a project hook
+			{
+				Config: testAccGitlabAPIObjectConfig(testProject.ID, "https://example.com/hook", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabAPIObjectHook(testProject.ID, "gitlab_api_object.this", "https://example.com/hook", true),
+					resource.TestCheckResourceAttrSet("gitlab_api_object.this", "response_body"),
+				),
+			},
+			// Update the project hook
+			{
+				Config: testAccGitlabAPIObjectConfig(testProject.ID, "https://example.com/hook-updated", false),
+				Check:  testAccCheckGitlabAPIObjectHook(testProject.ID, "gitlab_api_object.this", "https://example.com/hook-updated", false),
+			},
+			// Detect a change made outside of Terraform
+			{
+				PreConfig: func() {
+					hooks, _, err := testGitlabClient.Projects.ListProjectHooks(testProject.ID, nil)
+					if err != nil || len(hooks) != 1 {
+						t.Fatalf("failed to list project hooks: %v", err)
+					}
+					if _, _, err := testGitlabClient.Projects.EditProjectHook(testProject.ID, hooks[0].ID, &gitlab.EditProjectHookOptions{URL: gitlab.String("https://example.com/changed")}); err != nil {
+						t.Fatalf("failed to edit project hook: %v", err)
+					}
+				},
+				Config:             testAccGitlabAPIObjectConfig(testProject.ID, "https://example.com/hook-updated", false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Revert the change made outside of Terraform
+			{
+				Config: testAccGitlabAPIObjectConfig(testProject.ID, "https://example.com/hook-updated", false),
+				Check:  testAccCheckGitlabAPIObjectHook(testProject.ID, "gitlab_api_object.this", "https://example.com/hook-updated", false),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabAPIObjectHook(project int, resourceName string, url string, pushEvents bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+		hookID, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		hook, _, err := testGitlabClient.Projects.GetProjectHook(project, hookID)
+		if err != nil {
+			return err
+		}
+		if hook.URL != url {
+			return fmt.Errorf("got url %q expected %q", hook.URL, url)
+		}
+		if hook.PushEvents != pushEvents {
+			return fmt.Errorf("got push_events %v expected %v", hook.PushEvents, pushEvents)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabAPIObjectDestroy(project int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		hooks, _, err := testGitlabClient.Projects.ListProjectHooks(project, nil)
+		if err != nil {
+			return err
+		}
+		if len(hooks) != 0 {
+			return fmt.Errorf("project hooks still exist: %v", hooks)
+		}
+		return nil
+	}
+}
+
+func testAccGitlabAPIObjectConfig(project int, url string, pushEvents bool) string {
+	return fmt.Sprintf(`
+resource "gitlab_api_object" "this" {
+  create_path = "projects/%[1]d/hooks"
+  read_path   = "projects/%[1]d/hooks/{id}"
+
+  body = jsonencode({
+    url         = "%[2]s"
+    push_events = %[3]t
+  })
+
+  drift_detection_keys = ["url"]
+}
+`, project, url, pushEvents)
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestGitlab_apiObjectID(t *testing.T) {
+	testcases := []struct {
+		body        string
+		idAttribute string
+		expected    string
+		expectError bool
+	}{
+		{body: `{"id": 42}`, idAttribute: "id", expected: "42"},
+		{body: `{"id": 12345678901234567890}`, idAttribute: "id", expected: "12345678901234567890"},
+		{body: `{"key": "FOO"}`, idAttribute: "key", expected: "FOO"},
+		{body: `{"project": {"id": 1}}`, idAttribute: "project.id", expected: "1"},
+		{body: `{"name": "foo"}`, idAttribute: "id", expectError: true},
+		{body: `[{"id": 1}]`, idAttribute: "id", expectError: true},
+	}
+
+	for _, test := range testcases {
+		actual, err := apiObjectID([]byte(test.body), test.idAttribute)
+		if test.expectError {
+			if err == nil {
+				t.Fatalf("expected an error for %s at %q, got %q", test.body, test.idAttribute, actual)
+			}
+			continue
+		}
+		if err != nil || actual != test.expected {
+			t.Fatalf("FAIL\n\tbody: %s, id_attribute: %s\n\texpected: %s\n\tactual: %s (error: %v)", test.body, test.idAttribute, test.expected, actual, err)
+		}
+	}
+}
+
+func TestGitlab_apiObjectDriftedBody(t *testing.T) {
+	body := `{"url": "https://example.com/hook", "push_events": true, "token": "secret"}`
+	response := []byte(`{"id": 1, "url": "https://example.com/changed", "push_events": false}`)
+
+	actual, err := apiObjectDriftedBody(body, response, []string{"url", "token"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"push_events":true,"token":"secret","url":"https://example.com/changed"}`
+	if actual != expected {
+		t.Fatalf("FAIL\n\texpected: %s\n\tactual: %s", expected, actual)
+	}
+}
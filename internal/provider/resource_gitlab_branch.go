@@ -150,7 +150,7 @@ var commitSchema = &schema.Resource{
 }
 
 func resourceGitlabBranchCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	name := d.Get("name").(string)
 	project := d.Get("project").(string)
 	ref := d.Get("ref").(string)
@@ -170,7 +170,7 @@ func resourceGitlabBranchCreate(ctx context.Context, d *schema.ResourceData, met
 }
 
 func resourceGitlabBranchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, name, err := parseTwoPartID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -178,12 +178,10 @@ func resourceGitlabBranchRead(ctx context.Context, d *schema.ResourceData, meta
 
 	log.Printf("[DEBUG] read gitlab branch %s", name)
 	branch, resp, err := client.Branches.GetBranch(project, name, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, branch, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] recieved 404 for gitlab branch %s, removing from state", name)
-			d.SetId("")
-			return diag.FromErr(err)
-		}
 		log.Printf("[DEBUG] failed to read gitlab branch %s response %v", name, resp)
 		return diag.FromErr(err)
 	}
@@ -205,7 +203,7 @@ func resourceGitlabBranchRead(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGitlabBranchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, name, err := parseTwoPartID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	gitlab "github.com/xanzy/go-gitlab"
@@ -61,6 +62,12 @@ var _ = registerResource("gitlab_branch_protection", func() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffRequiresGitLab("allowed_to_push", "", true),
+			customizeDiffRequiresGitLab("allowed_to_merge", "", true),
+			customizeDiffRequiresGitLab("allowed_to_unprotect", "", true),
+			customizeDiffRequiresGitLab("code_owner_approval_required", "", true),
+		),
 		Schema: map[string]*schema.Schema{
 			"project": {
 				Description: "The id of the project.",
@@ -124,10 +131,18 @@ var _ = registerResource("gitlab_branch_protection", func() *schema.Resource {
 })
 
 func resourceGitlabBranchProtectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	branch := d.Get("branch").(string)
 
+	// Changes to the protected branches of a project conflict with each other when made concurrently.
+	locks := meta.(*providerMeta).locks
+	key := lockKey("project", project, "protected_branches")
+	if err := locks.lock(ctx, key); err != nil {
+		return diag.FromErr(err)
+	}
+	defer locks.unlock(key)
+
 	log.Printf("[DEBUG] create gitlab branch protection on branch %q for project %s", branch, project)
 
 	if d.IsNewResource() {
@@ -189,7 +204,7 @@ func resourceGitlabBranchProtectionCreate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceGitlabBranchProtectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, branch, err := projectAndBranchFromID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -199,11 +214,12 @@ func resourceGitlabBranchProtectionRead(ctx context.Context, d *schema.ResourceD
 
 	// Get protected branch by project ID/path and branch name
 	pb, _, err := client.ProtectedBranches.GetProtectedBranch(project, branch, gitlab.WithContext(ctx))
-	if err != nil {
-		log.Printf("[DEBUG] failed to read gitlab branch protection for project %s, branch %s: %s", project, branch, err)
-		d.SetId("")
+	if removeGoneFromState(ctx, d, pb, err) {
 		return nil
 	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	d.Set("project", project)
 	d.Set("branch", pb.Name)
@@ -256,11 +272,19 @@ func resourceGitlabBranchProtectionUpdate(ctx context.Context, d *schema.Resourc
 	// NOTE: At the time of writing, the only value that does not force re-creation is code_owner_approval_required,
 	// so therefore that is the only update that needs to be handled.
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	branch := d.Get("branch").(string)
 	codeOwnerApprovalRequired := d.Get("code_owner_approval_required").(bool)
 
+	// Changes to the protected branches of a project conflict with each other when made concurrently.
+	locks := meta.(*providerMeta).locks
+	key := lockKey("project", project, "protected_branches")
+	if err := locks.lock(ctx, key); err != nil {
+		return diag.FromErr(err)
+	}
+	defer locks.unlock(key)
+
 	log.Printf("[DEBUG] update gitlab branch protection for project %s, branch %s", project, branch)
 
 	options := &gitlab.RequireCodeOwnerApprovalsOptions{
@@ -281,10 +305,18 @@ func resourceGitlabBranchProtectionUpdate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceGitlabBranchProtectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	branch := d.Get("branch").(string)
 
+	// Changes to the protected branches of a project conflict with each other when made concurrently.
+	locks := meta.(*providerMeta).locks
+	key := lockKey("project", project, "protected_branches")
+	if err := locks.lock(ctx, key); err != nil {
+		return diag.FromErr(err)
+	}
+	defer locks.unlock(key)
+
 	log.Printf("[DEBUG] Delete gitlab protected branch %s for project %s", branch, project)
 
 	_, err := client.ProtectedBranches.UnprotectRepositoryBranches(project, branch, gitlab.WithContext(ctx))
@@ -28,17 +28,20 @@ var _ = registerResource("gitlab_cluster_agent", func() *schema.Resource {
 
 		CreateContext: resourceGitlabClusterAgentCreate,
 		ReadContext:   resourceGitlabClusterAgentRead,
+		UpdateContext: resourceGitlabClusterAgentUpdate,
 		DeleteContext: resourceGitlabClusterAgentDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
-		Schema: gitlabClusterAgentSchema(),
+		Schema: constructSchema(gitlabClusterAgentSchema(), map[string]*schema.Schema{
+			"deletion_protection": deletionProtectionSchema("agent"),
+		}),
 	}
 })
 
 func resourceGitlabClusterAgentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	options := gitlab.RegisterAgentOptions{
@@ -55,8 +58,13 @@ func resourceGitlabClusterAgentCreate(ctx context.Context, d *schema.ResourceDat
 	return resourceGitlabClusterAgentRead(ctx, d, meta)
 }
 
+func resourceGitlabClusterAgentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Only the `deletion_protection` can be updated, which is only stored in the state.
+	return resourceGitlabClusterAgentRead(ctx, d, meta)
+}
+
 func resourceGitlabClusterAgentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, agentID, err := resourceGitlabClusterAgentParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -64,12 +72,10 @@ func resourceGitlabClusterAgentRead(ctx context.Context, d *schema.ResourceData,
 
 	log.Printf("[DEBUG] read GitLab Agent for Kubernetes in project %s with id %d", project, agentID)
 	clusterAgent, _, err := client.ClusterAgents.GetAgent(project, agentID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, clusterAgent, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] read GitLab Agent for Kubernetes in project %s with id %d not found, removing from state", project, agentID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -81,7 +87,12 @@ func resourceGitlabClusterAgentRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabClusterAgentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
+
+	if diags := checkDeletionProtection(d, "gitlab_cluster_agent"); diags != nil {
+		return diags
+	}
+
 	project, agentID, err := resourceGitlabClusterAgentParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
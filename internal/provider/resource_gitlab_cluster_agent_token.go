@@ -34,7 +34,7 @@ var _ = registerResource("gitlab_cluster_agent_token", func() *schema.Resource {
 })
 
 func resourceGitlabClusterAgentTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	agentID := d.Get("agent_id").(int)
@@ -59,7 +59,7 @@ func resourceGitlabClusterAgentTokenCreate(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabClusterAgentTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, agentID, tokenID, err := resourceGitlabClusterAgentTokenParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -67,12 +67,10 @@ func resourceGitlabClusterAgentTokenRead(ctx context.Context, d *schema.Resource
 
 	log.Printf("[DEBUG] read token for GitLab Agent for Kubernetes %d in project %s with id %d", agentID, project, tokenID)
 	clusterAgentToken, _, err := client.ClusterAgents.GetAgentToken(project, agentID, tokenID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, clusterAgentToken, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] read token for GitLab Agent for Kubernetes %d in project %s with id %d not found, removing from state", agentID, project, tokenID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -84,7 +82,7 @@ func resourceGitlabClusterAgentTokenRead(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabClusterAgentTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, agentID, tokenID, err := resourceGitlabClusterAgentTokenParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -61,7 +61,7 @@ var _ = registerResource("gitlab_deploy_key", func() *schema.Resource {
 })
 
 func resourceGitlabDeployKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	options := &gitlab.AddDeployKeyOptions{
 		Title:   gitlab.String(d.Get("title").(string)),
@@ -82,7 +82,7 @@ func resourceGitlabDeployKeyCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabDeployKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	deployKeyID, err := strconv.Atoi(d.Id())
@@ -93,12 +93,10 @@ func resourceGitlabDeployKeyRead(ctx context.Context, d *schema.ResourceData, me
 	log.Printf("[DEBUG] read gitlab deploy key %s/%d", project, deployKeyID)
 
 	deployKey, _, err := client.DeployKeys.GetDeployKey(project, deployKeyID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, deployKey, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab deploy key not found %s/%d", project, deployKeyID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -110,7 +108,7 @@ func resourceGitlabDeployKeyRead(ctx context.Context, d *schema.ResourceData, me
 }
 
 func resourceGitlabDeployKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	deployKeyID, err := strconv.Atoi(d.Id())
@@ -102,7 +102,7 @@ func expiresAtSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
 }
 
 func resourceGitlabDeployTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, isProject := d.GetOk("project")
 	group, isGroup := d.GetOk("group")
 
@@ -160,7 +160,7 @@ func resourceGitlabDeployTokenCreate(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabDeployTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, isProject := d.GetOk("project")
 	group, isGroup := d.GetOk("group")
 	deployTokenID, err := strconv.Atoi(d.Id())
@@ -187,6 +187,9 @@ func resourceGitlabDeployTokenRead(ctx context.Context, d *schema.ResourceData,
 		}
 		for options.Page != 0 && deployToken == nil {
 			paginatedDeployTokens, resp, err := client.DeployTokens.ListProjectDeployTokens(project, &options, gitlab.WithContext(ctx))
+			if removeGoneFromState(ctx, d, nil, err) {
+				return nil
+			}
 			if err != nil {
 				return diag.FromErr(err)
 			}
@@ -201,6 +204,9 @@ func resourceGitlabDeployTokenRead(ctx context.Context, d *schema.ResourceData,
 		}
 		for options.Page != 0 && deployToken == nil {
 			paginatedDeployTokens, resp, err := client.DeployTokens.ListGroupDeployTokens(group, &options, gitlab.WithContext(ctx))
+			if removeGoneFromState(ctx, d, nil, err) {
+				return nil
+			}
 			if err != nil {
 				return diag.FromErr(err)
 			}
@@ -210,8 +216,7 @@ func resourceGitlabDeployTokenRead(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if deployToken == nil {
-		log.Printf("[DEBUG] GitLab deploy token %d in was not found, removing from state", deployTokenID)
-		d.SetId("")
+		removeGoneFromState(ctx, d, nil, errGone)
 		return nil
 	}
 
@@ -230,7 +235,7 @@ func resourceGitlabDeployTokenRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabDeployTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, isProject := d.GetOk("project")
 	group, isGroup := d.GetOk("group")
 	deployTokenID, err := strconv.Atoi(d.Id())
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -30,7 +31,7 @@ var _ = registerResource("gitlab_group", func() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: constructSchema(map[string]*schema.Schema{
 			"name": {
 				Description: "The name of this group.",
 				Type:        schema.TypeString,
@@ -61,6 +62,9 @@ var _ = registerResource("gitlab_group", func() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 			},
+			"deletion_protection":           deletionProtectionSchema("group"),
+			"permanently_remove_on_destroy": permanentlyRemoveOnDestroySchema("group"),
+			"marked_for_deletion_on":        markedForDeletionSchema("group"),
 			"lfs_enabled": {
 				Description: "Defaults to true. Enable/disable Large File Storage (LFS) for the projects in this group.",
 				Type:        schema.TypeBool,
@@ -155,12 +159,16 @@ var _ = registerResource("gitlab_group", func() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 			},
-		},
+		}, avatarSchema("group")),
+		CustomizeDiff: customdiff.All(
+			customizeDiffAvatarHash,
+			customizeDiffRestoreMarkedForDeletion("marked_for_deletion_on"),
+		),
 	}
 })
 
 func resourceGitlabGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	options := &gitlab.CreateGroupOptions{
 		Name:                 gitlab.String(d.Get("name").(string)),
 		LFSEnabled:           gitlab.Bool(d.Get("lfs_enabled").(bool)),
@@ -252,26 +260,29 @@ func resourceGitlabGroupCreate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
+	if _, ok := d.GetOk("avatar"); ok {
+		if err := updateAvatar(ctx, client, d, fmt.Sprintf("groups/%s", d.Id())); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceGitlabGroupRead(ctx, d, meta)
 }
 
 func resourceGitlabGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	log.Printf("[DEBUG] read gitlab group %s", d.Id())
 
 	group, _, err := client.Groups.GetGroup(d.Id(), nil, gitlab.WithContext(ctx))
+	// A group marked for deletion stays in the state, to restore it if it's still configured.
+	if removeGoneFromState(ctx, d, nil, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab group %s not found so removing from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
-	if group.MarkedForDeletionOn != nil {
-		log.Printf("[DEBUG] gitlab group %s is marked for deletion", d.Id())
-		d.SetId("")
-		return nil
+	if isMarkedForDeletion(group) {
+		log.Printf("[DEBUG] gitlab group %s is marked for deletion, it's restored if it's still configured", d.Id())
 	}
 
 	d.SetId(fmt.Sprintf("%d", group.ID))
@@ -280,6 +291,12 @@ func resourceGitlabGroupRead(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("full_path", group.FullPath)
 	d.Set("full_name", group.FullName)
 	d.Set("web_url", group.WebURL)
+	d.Set("avatar_url", group.AvatarURL)
+	if group.MarkedForDeletionOn != nil {
+		d.Set("marked_for_deletion_on", group.MarkedForDeletionOn.String())
+	} else {
+		d.Set("marked_for_deletion_on", "")
+	}
 	d.Set("description", group.Description)
 	d.Set("lfs_enabled", group.LFSEnabled)
 	d.Set("request_access_enabled", group.RequestAccessEnabled)
@@ -301,7 +318,14 @@ func resourceGitlabGroupRead(ctx context.Context, d *schema.ResourceData, meta i
 }
 
 func resourceGitlabGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
+
+	if oldMarkedForDeletionOn, _ := d.GetChange("marked_for_deletion_on"); oldMarkedForDeletionOn.(string) != "" {
+		log.Printf("[DEBUG] restore gitlab group %s, because it's marked for deletion", d.Id())
+		if _, _, err := client.Groups.RestoreGroup(d.Id(), gitlab.WithContext(ctx)); err != nil {
+			return diag.Errorf("failed to restore group %s, which is marked for deletion: %s", d.Id(), err)
+		}
+	}
 
 	options := &gitlab.UpdateGroupOptions{}
 
@@ -385,6 +409,12 @@ func resourceGitlabGroupUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
+	if avatarChanged(d) {
+		if err := updateAvatar(ctx, client, d, fmt.Sprintf("groups/%s", d.Id())); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceGitlabGroupRead(ctx, d, meta)
 }
 
@@ -412,7 +442,12 @@ func transferSubGroup(ctx context.Context, d *schema.ResourceData, client *gitla
 }
 
 func resourceGitlabGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
+
+	if diags := checkDeletionProtection(d, "gitlab_group"); diags != nil {
+		return diags
+	}
+
 	log.Printf("[DEBUG] Delete gitlab group %s", d.Id())
 
 	_, err := client.Groups.DeleteGroup(d.Id(), gitlab.WithContext(ctx))
@@ -420,6 +455,22 @@ func resourceGitlabGroupDelete(ctx context.Context, d *schema.ResourceData, meta
 		return diag.Errorf("error deleting group %s: %s", d.Id(), err)
 	}
 
+	permanentlyRemoveOnDestroy := d.Get("permanently_remove_on_destroy").(bool)
+	if permanentlyRemoveOnDestroy {
+		group, _, err := client.Groups.GetGroup(d.Id(), nil, gitlab.WithContext(ctx))
+		if err != nil {
+			if is404(err) {
+				return nil
+			}
+			return diag.FromErr(err)
+		}
+		if isMarkedForDeletion(group) {
+			if err := permanentlyRemove(ctx, client, fmt.Sprintf("groups/%d", group.ID), group.FullPath); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
 	// Wait for the group to be deleted.
 	// Deleting a group in gitlab is async.
 	stateConf := &resource.StateChangeConf{
@@ -434,7 +485,7 @@ func resourceGitlabGroupDelete(ctx context.Context, d *schema.ResourceData, meta
 				log.Printf("[ERROR] Received error: %#v", err)
 				return out, "Error", err
 			}
-			if out.MarkedForDeletionOn != nil {
+			if isMarkedForDeletion(out) && !permanentlyRemoveOnDestroy {
 				// Represents a Gitlab EE soft-delete
 				return out, "Deleted", nil
 			}
@@ -113,7 +113,7 @@ var _ = registerResource("gitlab_group_access_token", func() *schema.Resource {
 })
 
 func resourceGitlabGroupAccessTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	group := d.Get("group").(string)
 	options := &gitlab.CreateGroupAccessTokenOptions{
@@ -158,7 +158,7 @@ func resourceGitlabGroupAccessTokenRead(ctx context.Context, d *schema.ResourceD
 		return diag.Errorf("Error parsing ID: %s", d.Id())
 	}
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	groupAccessTokenId, err := strconv.Atoi(tokenId)
 	if err != nil {
@@ -167,12 +167,10 @@ func resourceGitlabGroupAccessTokenRead(ctx context.Context, d *schema.ResourceD
 
 	log.Printf("[DEBUG] read gitlab GroupAccessToken %d, group ID %s", groupAccessTokenId, group)
 	groupAccessToken, _, err := client.GroupAccessTokens.GetGroupAccessToken(group, groupAccessTokenId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, groupAccessToken, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] GitLab GroupAccessToken %d, group ID %s not found, removing from state", groupAccessTokenId, group)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -201,7 +199,7 @@ func resourceGitlabGroupAccessTokenDelete(ctx context.Context, d *schema.Resourc
 		return diag.Errorf("Error parsing ID: %s", d.Id())
 	}
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	groupAccessTokenId, err := strconv.Atoi(tokenId)
 	if err != nil {
@@ -56,7 +56,7 @@ var _ = registerResource("gitlab_group_badge", func() *schema.Resource {
 })
 
 func resourceGitlabGroupBadgeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	groupID := d.Get("group").(string)
 	options := &gitlab.AddGroupBadgeOptions{
 		LinkURL:  gitlab.String(d.Get("link_url").(string)),
@@ -78,7 +78,7 @@ func resourceGitlabGroupBadgeCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabGroupBadgeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	ids := strings.Split(d.Id(), ":")
 	groupID := ids[0]
 	badgeID, err := strconv.Atoi(ids[1])
@@ -89,12 +89,10 @@ func resourceGitlabGroupBadgeRead(ctx context.Context, d *schema.ResourceData, m
 	log.Printf("[DEBUG] read gitlab group badge %s/%d", groupID, badgeID)
 
 	badge, _, err := client.GroupBadges.GetGroupBadge(groupID, badgeID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, badge, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] group badge %d in group %s doesn't exist anymore, removing from state", badgeID, groupID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -103,7 +101,7 @@ func resourceGitlabGroupBadgeRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceGitlabGroupBadgeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	ids := strings.Split(d.Id(), ":")
 	groupID := ids[0]
 	badgeID, err := strconv.Atoi(ids[1])
@@ -127,7 +125,7 @@ func resourceGitlabGroupBadgeUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabGroupBadgeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	ids := strings.Split(d.Id(), ":")
 	groupID := ids[0]
 	badgeID, err := strconv.Atoi(ids[1])
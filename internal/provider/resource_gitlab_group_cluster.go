@@ -123,7 +123,7 @@ var _ = registerResource("gitlab_group_cluster", func() *schema.Resource {
 })
 
 func resourceGitlabGroupClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group := d.Get("group").(string)
 
 	pk := gitlab.AddGroupPlatformKubernetesOptions{
@@ -173,7 +173,7 @@ func resourceGitlabGroupClusterCreate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabGroupClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	group, clusterId, err := groupIdAndClusterIdFromId(d.Id())
 	if err != nil {
@@ -183,12 +183,10 @@ func resourceGitlabGroupClusterRead(ctx context.Context, d *schema.ResourceData,
 	log.Printf("[DEBUG] read gitlab group cluster %q/%d", group, clusterId)
 
 	cluster, _, err := client.GroupCluster.GetCluster(group, clusterId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, cluster, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab group cluster not found %s/%d", group, clusterId)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -215,7 +213,7 @@ func resourceGitlabGroupClusterRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabGroupClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	group, clusterId, err := groupIdAndClusterIdFromId(d.Id())
 	if err != nil {
@@ -270,7 +268,7 @@ func resourceGitlabGroupClusterUpdate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabGroupClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group, clusterId, err := groupIdAndClusterIdFromId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
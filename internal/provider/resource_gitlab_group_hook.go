@@ -29,7 +29,7 @@ var _ = registerResource("gitlab_group_hook", func() *schema.Resource {
 })
 
 func resourceGitlabGroupHookCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group := d.Get("group").(string)
 	options := &gitlab.AddGroupHookOptions{
 		URL:                      gitlab.String(d.Get("url").(string)),
@@ -74,14 +74,12 @@ func resourceGitlabGroupHookRead(ctx context.Context, d *schema.ResourceData, me
 	}
 	log.Printf("[DEBUG] read gitlab group hook %s/%d", group, hookID)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	hook, _, err := client.Groups.GetGroupHook(group, hookID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, hook, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab group hook not found %s/%d, removing from state", group, hookID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -98,7 +96,7 @@ func resourceGitlabGroupHookUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	options := &gitlab.EditGroupHookOptions{
 		URL:                      gitlab.String(d.Get("url").(string)),
 		PushEvents:               gitlab.Bool(d.Get("push_events").(bool)),
@@ -139,7 +137,7 @@ func resourceGitlabGroupHookDelete(ctx context.Context, d *schema.ResourceData,
 	}
 	log.Printf("[DEBUG] Delete gitlab group hook %s/%d", group, hookID)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	_, err = client.Groups.DeleteGroupHook(group, hookID, gitlab.WithContext(ctx))
 	if err != nil {
 		return diag.FromErr(err)
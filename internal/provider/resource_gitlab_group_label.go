@@ -53,7 +53,7 @@ var _ = registerResource("gitlab_group_label", func() *schema.Resource {
 })
 
 func resourceGitlabGroupLabelCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group := d.Get("group").(string)
 	options := &gitlab.CreateGroupLabelOptions{
 		Name:  gitlab.String(d.Get("name").(string)),
@@ -76,18 +76,16 @@ func resourceGitlabGroupLabelCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabGroupLabelRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group := d.Get("group").(string)
 	labelName := d.Id()
 	log.Printf("[DEBUG] read gitlab group label %s/%s", group, labelName)
 
 	label, _, err := client.GroupLabels.GetGroupLabel(group, labelName, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, label, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] failed to read gitlab label %s/%s, removing from state", group, labelName)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 	d.Set("description", label.Description)
@@ -97,7 +95,7 @@ func resourceGitlabGroupLabelRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceGitlabGroupLabelUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group := d.Get("group").(string)
 	options := &gitlab.UpdateGroupLabelOptions{
 		Name:  gitlab.String(d.Get("name").(string)),
@@ -119,7 +117,7 @@ func resourceGitlabGroupLabelUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabGroupLabelDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group := d.Get("group").(string)
 	log.Printf("[DEBUG] Delete gitlab group label %s", d.Id())
 	options := &gitlab.DeleteGroupLabelOptions{
@@ -131,7 +129,7 @@ func resourceGitlabGroupLabelDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabGroupLabelImporter(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	parts := strings.SplitN(d.Id(), ":", 2)
 	if len(parts) != 2 {
 		return nil, fmt.Errorf("invalid label id (should be <group ID>:<label name>): %s", d.Id())
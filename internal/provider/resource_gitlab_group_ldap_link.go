@@ -74,7 +74,7 @@ var _ = registerResource("gitlab_group_ldap_link", func() *schema.Resource {
 })
 
 func resourceGitlabGroupLdapLinkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	groupId := d.Get("group_id").(string)
 	cn := d.Get("cn").(string)
@@ -115,7 +115,7 @@ func resourceGitlabGroupLdapLinkCreate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabGroupLdapLinkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	groupId := d.Get("group_id").(string)
 
 	// Try to fetch all group links from GitLab
@@ -152,8 +152,7 @@ func resourceGitlabGroupLdapLinkRead(ctx context.Context, d *schema.ResourceData
 		}
 
 		if !found {
-			d.SetId("")
-			return diag.Errorf("LdapLink %s does not exist.", d.Id())
+			removeGoneFromState(ctx, d, nil, errGone)
 		}
 	}
 
@@ -161,7 +160,7 @@ func resourceGitlabGroupLdapLinkRead(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabGroupLdapLinkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	groupId := d.Get("group_id").(string)
 	cn := d.Get("cn").(string)
 	ldap_provider := d.Get("ldap_provider").(string)
@@ -71,7 +71,7 @@ var _ = registerResource("gitlab_group_membership", func() *schema.Resource {
 })
 
 func resourceGitlabGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	userId := d.Get("user_id").(int)
 	groupId := d.Get("group_id").(string)
@@ -95,7 +95,7 @@ func resourceGitlabGroupMembershipCreate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabGroupMembershipRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	id := d.Id()
 	log.Printf("[DEBUG] read gitlab group groupMember %s", id)
 
@@ -105,12 +105,10 @@ func resourceGitlabGroupMembershipRead(ctx context.Context, d *schema.ResourceDa
 	}
 
 	groupMember, _, err := client.GroupMembers.GetGroupMember(groupId, userId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, groupMember, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab group membership for %s not found so removing from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -131,7 +129,7 @@ func groupIdAndUserIdFromId(id string) (string, int, error) {
 }
 
 func resourceGitlabGroupMembershipUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	userId := d.Get("user_id").(int)
 	groupId := d.Get("group_id").(string)
@@ -153,7 +151,7 @@ func resourceGitlabGroupMembershipUpdate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	id := d.Id()
 	groupId, userId, err := groupIdAndUserIdFromId(id)
@@ -8,7 +8,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	gitlab "github.com/xanzy/go-gitlab"
-	"log"
 )
 
 var _ = registerResource("gitlab_group_project_file_template", func() *schema.Resource {
@@ -53,23 +52,16 @@ For more information about which file types are available as templates, view
 })
 
 func resourceGitLabGroupProjectFileTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	groupID := d.Get("group_id").(int)
 	group, _, err := client.Groups.GetGroup(groupID, nil, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, group, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab group %d not found, removing from state", groupID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
-	if group.MarkedForDeletionOn != nil {
-		log.Printf("[DEBUG] gitlab group %s is marked for deletion, removing from state", d.Id())
-		d.SetId("")
-		return nil
-	}
 
 	d.SetId(fmt.Sprintf("%d", group.ID))
 	d.Set("file_template_project_id", group.FileTemplateProjectID)
@@ -78,7 +70,7 @@ func resourceGitLabGroupProjectFileTemplateRead(ctx context.Context, d *schema.R
 }
 
 func resourceGitLabGroupProjectFileTemplateCreateOrUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	groupID := d.Get("group_id").(int)
 	projectID := gitlab.Int(d.Get("file_template_project_id").(int))
@@ -97,7 +89,7 @@ func resourceGitLabGroupProjectFileTemplateCreateOrUpdate(ctx context.Context, d
 }
 
 func resourceGitLabGroupProjectFileTemplateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	groupID := d.Get("group_id").(int)
 	options := &gitlab.UpdateGroupOptions{}
 
@@ -57,7 +57,7 @@ var _ = registerResource("gitlab_group_saml_link", func() *schema.Resource {
 })
 
 func resourceGitlabGroupSamlLinkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	group := d.Get("group").(string)
 	samlGroupName := d.Get("saml_group_name").(string)
@@ -79,7 +79,7 @@ func resourceGitlabGroupSamlLinkCreate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabGroupSamlLinkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group, samlGroupName, parse_err := parseTwoPartID(d.Id())
 	if parse_err != nil {
 		return diag.FromErr(parse_err)
@@ -88,12 +88,10 @@ func resourceGitlabGroupSamlLinkRead(ctx context.Context, d *schema.ResourceData
 	// Try to fetch all group links from GitLab
 	log.Printf("[DEBUG] Read GitLab Group SAML Link for group %q", group)
 	samlLink, _, err := client.Groups.GetGroupSAMLLink(group, samlGroupName, nil, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, samlLink, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] GitLab SAML Group Link %s for group ID %s not found, removing from state", samlGroupName, group)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -105,7 +103,7 @@ func resourceGitlabGroupSamlLinkRead(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabGroupSamlLinkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group, samlGroupName, parse_err := parseTwoPartID(d.Id())
 	if parse_err != nil {
 		return diag.FromErr(parse_err)
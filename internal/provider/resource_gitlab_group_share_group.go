@@ -67,7 +67,7 @@ func resourceGitlabGroupShareGroupCreate(ctx context.Context, d *schema.Resource
 		ExpiresAt:   gitlab.String(d.Get("expires_at").(string)),
 	}
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	log.Printf("[DEBUG] create gitlab group share for %d in %s", shareGroupId, groupId)
 
 	_, _, err := client.GroupMembers.ShareWithGroup(groupId, options, gitlab.WithContext(ctx))
@@ -82,7 +82,7 @@ func resourceGitlabGroupShareGroupCreate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabGroupShareGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	id := d.Id()
 	log.Printf("[DEBUG] read gitlab shared groups %s", id)
 
@@ -93,12 +93,10 @@ func resourceGitlabGroupShareGroupRead(ctx context.Context, d *schema.ResourceDa
 
 	// Query main group
 	group, _, err := client.Groups.GetGroup(groupId, nil, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, group, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab group %s not found so removing from state", groupId)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -121,13 +119,12 @@ func resourceGitlabGroupShareGroupRead(ctx context.Context, d *schema.ResourceDa
 		}
 	}
 
-	log.Printf("[DEBUG] gitlab shared group %s not found so removing from state", id)
-	d.SetId("")
+	removeGoneFromState(ctx, d, nil, errGone)
 	return nil
 }
 
 func resourceGitlabGroupShareGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	id := d.Id()
 
 	groupId, sharedGroupId, err := groupIdsFromId(id)
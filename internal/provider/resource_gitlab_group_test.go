@@ -4,6 +4,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -289,6 +290,99 @@ func TestAccGitlabGroup_PreventForkingOutsideGroup(t *testing.T) {
 	})
 }
 
+func TestAccGitlabGroup_avatar(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckGitlabGroupDestroy,
+		Steps: []resource.TestStep{
+			// Create a group with an avatar
+			{
+				Config: testAccGitlabGroupAvatarConfig(rInt, "avatar.png"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_group.foo", "avatar_url"),
+					resource.TestCheckResourceAttr("gitlab_group.foo", "avatar_hash", "8d29d9c393facb9d86314eb347a03fde503f2c0422bf55af7df086deb126107e"),
+				),
+			},
+			{
+				ResourceName:            "gitlab_group.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"avatar", "avatar_hash"},
+			},
+			// Update the avatar
+			{
+				Config: testAccGitlabGroupAvatarConfig(rInt, "avatar-update.png"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_group.foo", "avatar_url"),
+					resource.TestCheckResourceAttr("gitlab_group.foo", "avatar_hash", "a58bd926fd3baabd41c56e810f62ade8705d18a4e280fb35764edb4b778444db"),
+				),
+			},
+			// Remove the avatar
+			{
+				SkipFunc: isGitLabVersionLessThan(context.Background(), testGitlabClient, "15.4"),
+				Config:   testAccGitlabGroupAvatarConfig(rInt, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_group.foo", "avatar_url", ""),
+					resource.TestCheckResourceAttr("gitlab_group.foo", "avatar_hash", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGitlabGroup_delayedDeletion(t *testing.T) {
+	var group gitlab.Group
+	rInt := acctest.RandInt()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			// The group must be gone, instead of only being marked for deletion.
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != "gitlab_group" {
+					continue
+				}
+				if _, _, err := testGitlabClient.Groups.GetGroup(rs.Primary.ID, nil); !is404(err) {
+					return fmt.Errorf("expected group %s to be removed permanently, got: %v", rs.Primary.ID, err)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				SkipFunc: isRunningInCE,
+				Config:   testAccGitlabGroupPermanentlyRemoveOnDestroyConfig(rInt, true),
+				Check:    testAccCheckGitlabGroupExists("gitlab_group.foo", &group),
+			},
+			// Mark the group for deletion outside of Terraform, so that it's restored
+			{
+				SkipFunc: isRunningInCE,
+				PreConfig: func() {
+					if _, err := testGitlabClient.Groups.DeleteGroup(group.ID); err != nil {
+						t.Fatalf("failed to delete group: %v", err)
+					}
+				},
+				Config: testAccGitlabGroupPermanentlyRemoveOnDestroyConfig(rInt, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_group.foo", "marked_for_deletion_on", ""),
+					func(*terraform.State) error {
+						restored, _, err := testGitlabClient.Groups.GetGroup(group.ID, nil)
+						if err != nil {
+							return err
+						}
+						if restored.MarkedForDeletionOn != nil {
+							return fmt.Errorf("expected group %d to be restored, it's still marked for deletion", group.ID)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckGitlabGroupDisappears(group *gitlab.Group) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		_, err := testGitlabClient.Groups.DeleteGroup(group.ID)
@@ -649,3 +743,39 @@ resource "gitlab_group" "foo" {
 }
   `, rInt, rInt)
 }
+
+func testAccGitlabGroupAvatarConfig(rInt int, avatar string) string {
+	var avatarConfig string
+	if avatar != "" {
+		avatarConfig = fmt.Sprintf(`
+  avatar      = "${path.module}/testdata/gitlab_topic/%[1]s"
+  avatar_hash = filesha256("${path.module}/testdata/gitlab_topic/%[1]s")`, avatar)
+	}
+	return fmt.Sprintf(`
+resource "gitlab_group" "foo" {
+  name = "foo-name-%d"
+  path = "foo-path-%d"
+  description = "Terraform acceptance tests"
+  %s
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+  `, rInt, rInt, avatarConfig)
+}
+
+func testAccGitlabGroupPermanentlyRemoveOnDestroyConfig(rInt int, permanentlyRemove bool) string {
+	return fmt.Sprintf(`
+resource "gitlab_group" "foo" {
+  name = "foo-name-%d"
+  path = "foo-path-%d"
+  description = "Terraform acceptance tests"
+  permanently_remove_on_destroy = %t
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+  `, rInt, rInt, permanentlyRemove)
+}
@@ -30,7 +30,7 @@ var _ = registerResource("gitlab_group_variable", func() *schema.Resource {
 })
 
 func resourceGitlabGroupVariableCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	group := d.Get("group").(string)
 	key := d.Get("key").(string)
@@ -62,7 +62,7 @@ func resourceGitlabGroupVariableCreate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabGroupVariableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	group, key, err := parseTwoPartID(d.Id())
 	if err != nil {
@@ -84,12 +84,10 @@ func resourceGitlabGroupVariableRead(ctx context.Context, d *schema.ResourceData
 		gitlab.WithContext(ctx),
 		withEnvironmentScopeFilter(ctx, scope),
 	)
+	if removeGoneFromState(ctx, d, v, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab group variable not found %s/%s", group, key)
-			d.SetId("")
-			return nil
-		}
 		return augmentVariableClientError(d, err)
 	}
 
@@ -101,7 +99,7 @@ func resourceGitlabGroupVariableRead(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabGroupVariableUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	group := d.Get("group").(string)
 	key := d.Get("key").(string)
@@ -134,7 +132,7 @@ func resourceGitlabGroupVariableUpdate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabGroupVariableDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	group := d.Get("group").(string)
 	key := d.Get("key").(string)
 	environmentScope := d.Get("environment_scope").(string)
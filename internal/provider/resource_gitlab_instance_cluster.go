@@ -122,7 +122,7 @@ var _ = registerResource("gitlab_instance_cluster", func() *schema.Resource {
 })
 
 func resourceGitlabInstanceClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	pk := gitlab.AddPlatformKubernetesOptions{
 		APIURL: gitlab.String(d.Get("kubernetes_api_url").(string)),
@@ -171,7 +171,7 @@ func resourceGitlabInstanceClusterCreate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabInstanceClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	clusterId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -181,12 +181,10 @@ func resourceGitlabInstanceClusterRead(ctx context.Context, d *schema.ResourceDa
 	log.Printf("[DEBUG] read gitlab instance cluster %d", clusterId)
 
 	cluster, _, err := client.InstanceCluster.GetCluster(clusterId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, cluster, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab instance cluster not found %d", clusterId)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -213,7 +211,7 @@ func resourceGitlabInstanceClusterRead(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabInstanceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	clusterId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -272,7 +270,7 @@ func resourceGitlabInstanceClusterUpdate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabInstanceClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	clusterId, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -28,7 +28,7 @@ var _ = registerResource("gitlab_instance_variable", func() *schema.Resource {
 })
 
 func resourceGitlabInstanceVariableCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	key := d.Get("key").(string)
 	value := d.Get("value").(string)
@@ -55,19 +55,17 @@ func resourceGitlabInstanceVariableCreate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceGitlabInstanceVariableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	key := d.Id()
 
 	log.Printf("[DEBUG] read gitlab instance level CI variable %s", key)
 
 	v, _, err := client.InstanceVariables.GetVariable(key, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, v, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab instance level CI variable for %s not found so removing from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return augmentVariableClientError(d, err)
 	}
 
@@ -85,7 +83,7 @@ func resourceGitlabInstanceVariableRead(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceGitlabInstanceVariableUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	key := d.Get("key").(string)
 	value := d.Get("value").(string)
@@ -109,7 +107,7 @@ func resourceGitlabInstanceVariableUpdate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceGitlabInstanceVariableDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	key := d.Get("key").(string)
 	log.Printf("[DEBUG] Delete gitlab instance level CI variable %s", key)
 
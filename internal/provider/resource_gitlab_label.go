@@ -56,7 +56,7 @@ var _ = registerResource("gitlab_label", func() *schema.Resource {
 })
 
 func resourceGitlabLabelCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	options := &gitlab.CreateLabelOptions{
 		Name:  gitlab.String(d.Get("name").(string)),
@@ -80,18 +80,16 @@ func resourceGitlabLabelCreate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGitlabLabelRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	labelName := d.Id()
 	log.Printf("[DEBUG] read gitlab label %s/%s", project, labelName)
 
 	label, _, err := client.Labels.GetLabel(project, labelName, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, label, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] failed to read gitlab label %s/%s", project, labelName)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -102,7 +100,7 @@ func resourceGitlabLabelRead(ctx context.Context, d *schema.ResourceData, meta i
 }
 
 func resourceGitlabLabelUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	options := &gitlab.UpdateLabelOptions{
 		Name:  gitlab.String(d.Get("name").(string)),
@@ -124,7 +122,7 @@ func resourceGitlabLabelUpdate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGitlabLabelDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	log.Printf("[DEBUG] Delete gitlab label %s", d.Id())
 	options := &gitlab.DeleteLabelOptions{
@@ -140,7 +138,7 @@ func resourceGitlabLabelDelete(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGitlabLabelImporter(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	parts := strings.SplitN(d.Id(), ":", 2)
 	if len(parts) != 2 {
 		return nil, fmt.Errorf("invalid label id (should be <project ID>.<label name>): %s", d.Id())
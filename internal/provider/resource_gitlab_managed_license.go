@@ -62,7 +62,7 @@ var _ = registerResource("gitlab_managed_license", func() *schema.Resource {
 })
 
 func resourceGitlabManagedLicenseCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	approvalStatus, err := stringToApprovalStatus(ctx, client, d.Get("approval_status").(string))
@@ -89,7 +89,7 @@ func resourceGitlabManagedLicenseCreate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceGitlabManagedLicenseDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, licenseId, err := projectIdAndLicenseIdFromId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -105,7 +105,7 @@ func resourceGitlabManagedLicenseDelete(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceGitlabManagedLicenseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, licenseId, err := projectIdAndLicenseIdFromId(d.Id())
 	if err != nil {
 		diag.FromErr(err)
@@ -113,12 +113,10 @@ func resourceGitlabManagedLicenseRead(ctx context.Context, d *schema.ResourceDat
 
 	log.Printf("[DEBUG] read gitlab Managed License for project/licenseId %s/%d", project, licenseId)
 	license, _, err := client.ManagedLicenses.GetManagedLicense(project, licenseId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, license, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] Managed License %s:%d no longer exists and is being removed from state", project, licenseId)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -130,7 +128,7 @@ func resourceGitlabManagedLicenseRead(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabManagedLicenseUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, licenseId, err := projectIdAndLicenseIdFromId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -95,9 +94,9 @@ var _ = registerResource("gitlab_personal_access_token", func() *schema.Resource
 })
 
 func resourceGitlabPersonalAccessTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
-	currentUserAdmin, err := isCurrentUserAdmin(ctx, client)
+	currentUserAdmin, err := meta.(*providerMeta).isCurrentUserAdmin(ctx)
 	if err != nil {
 		return diag.Errorf("[ERROR] cannot query the user API for current user: %v", err)
 	}
@@ -136,7 +135,7 @@ func resourceGitlabPersonalAccessTokenCreate(ctx context.Context, d *schema.Reso
 }
 
 func resourceGitlabPersonalAccessTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	userID, tokenID, err := resourceGitLabPersonalAccessTokenParseId(d.Id())
 	if err != nil {
@@ -146,13 +145,9 @@ func resourceGitlabPersonalAccessTokenRead(ctx context.Context, d *schema.Resour
 	log.Printf("[DEBUG] read gitlab PersonalAccessToken %d, user ID %d", tokenID, userID)
 
 	personalAccessToken, err := resourceGitlabPersonalAccessTokenFind(ctx, client, userID, tokenID)
-	if errors.Is(err, errResourceGitlabPersonalAccessTokenNotFound) {
-		log.Printf("[DEBUG] failed to read gitlab PersonalAccessToken %d, user ID %d", tokenID, userID)
-		d.SetId("")
-
+	if removeGoneFromState(ctx, d, nil, err) {
 		return nil
 	}
-
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -174,7 +169,7 @@ func resourceGitlabPersonalAccessTokenRead(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabPersonalAccessTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	_, tokenID, err := resourceGitLabPersonalAccessTokenParseId(d.Id())
 	if err != nil {
@@ -190,7 +185,7 @@ func resourceGitlabPersonalAccessTokenDelete(ctx context.Context, d *schema.Reso
 	return nil
 }
 
-var errResourceGitlabPersonalAccessTokenNotFound = errors.New("personal access token not found")
+var errResourceGitlabPersonalAccessTokenNotFound = fmt.Errorf("personal access token %w", errGone)
 
 // resourceGitlabPersonalAccessTokenFind finds the personal access token with the specified tokenID.
 // It returns a errResourceGitlabPersonalAccessTokenNotFound error if the token is not found or in a revoked state.
@@ -65,7 +65,7 @@ var _ = registerResource("gitlab_pipeline_schedule", func() *schema.Resource {
 })
 
 func resourceGitlabPipelineScheduleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	options := &gitlab.CreatePipelineScheduleOptions{
 		Description:  gitlab.String(d.Get("description").(string)),
@@ -88,7 +88,7 @@ func resourceGitlabPipelineScheduleCreate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceGitlabPipelineScheduleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	pipelineScheduleID, err := strconv.Atoi(d.Id())
 
@@ -99,12 +99,10 @@ func resourceGitlabPipelineScheduleRead(ctx context.Context, d *schema.ResourceD
 	log.Printf("[DEBUG] read gitlab PipelineSchedule %s/%d", project, pipelineScheduleID)
 
 	pipelineSchedule, _, err := client.PipelineSchedules.GetPipelineSchedule(project, pipelineScheduleID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, pipelineSchedule, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] PipelineSchedule %d in project %s does not exist, removing from state", pipelineScheduleID, project)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -117,7 +115,7 @@ func resourceGitlabPipelineScheduleRead(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceGitlabPipelineScheduleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	options := &gitlab.EditPipelineScheduleOptions{
 		Description:  gitlab.String(d.Get("description").(string)),
@@ -164,7 +162,7 @@ func resourceGitlabPipelineScheduleUpdate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceGitlabPipelineScheduleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	log.Printf("[DEBUG] Delete gitlab PipelineSchedule %s", d.Id())
 
@@ -55,7 +55,7 @@ var _ = registerResource("gitlab_pipeline_schedule_variable", func() *schema.Res
 })
 
 func resourceGitlabPipelineScheduleVariableCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	scheduleID := d.Get("pipeline_schedule_id").(int)
 
@@ -78,7 +78,7 @@ func resourceGitlabPipelineScheduleVariableCreate(ctx context.Context, d *schema
 }
 
 func resourceGitlabPipelineScheduleVariableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	scheduleID := d.Get("pipeline_schedule_id").(int)
 	pipelineVariableKey := d.Get("key").(string)
@@ -86,6 +86,9 @@ func resourceGitlabPipelineScheduleVariableRead(ctx context.Context, d *schema.R
 	log.Printf("[DEBUG] read gitlab PipelineSchedule %s/%d", project, scheduleID)
 
 	pipelineSchedule, _, err := client.PipelineSchedules.GetPipelineSchedule(project, scheduleID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, pipelineSchedule, err) {
+		return nil
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -102,15 +105,14 @@ func resourceGitlabPipelineScheduleVariableRead(ctx context.Context, d *schema.R
 		}
 	}
 	if !found {
-		log.Printf("[DEBUG] pipeline schedule variable not found %s/%d/%s", project, scheduleID, pipelineVariableKey)
-		d.SetId("")
+		removeGoneFromState(ctx, d, nil, errGone)
 	}
 
 	return nil
 }
 
 func resourceGitlabPipelineScheduleVariableUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	variableKey := d.Get("key").(string)
 	scheduleID := d.Get("pipeline_schedule_id").(int)
@@ -132,7 +134,7 @@ func resourceGitlabPipelineScheduleVariableUpdate(ctx context.Context, d *schema
 }
 
 func resourceGitlabPipelineScheduleVariableDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	variableKey := d.Get("key").(string)
 	scheduleID := d.Get("pipeline_schedule_id").(int)
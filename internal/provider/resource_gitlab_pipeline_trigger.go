@@ -48,7 +48,7 @@ var _ = registerResource("gitlab_pipeline_trigger", func() *schema.Resource {
 })
 
 func resourceGitlabPipelineTriggerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	options := &gitlab.AddPipelineTriggerOptions{
 		Description: gitlab.String(d.Get("description").(string)),
@@ -67,7 +67,7 @@ func resourceGitlabPipelineTriggerCreate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabPipelineTriggerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	pipelineTriggerID, err := strconv.Atoi(d.Id())
 
@@ -78,12 +78,10 @@ func resourceGitlabPipelineTriggerRead(ctx context.Context, d *schema.ResourceDa
 	log.Printf("[DEBUG] read gitlab PipelineTrigger %s/%d", project, pipelineTriggerID)
 
 	pipelineTrigger, _, err := client.PipelineTriggers.GetPipelineTrigger(project, pipelineTriggerID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, pipelineTrigger, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab pipeline trigger not found %s/%d", project, pipelineTriggerID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -94,7 +92,7 @@ func resourceGitlabPipelineTriggerRead(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabPipelineTriggerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	options := &gitlab.EditPipelineTriggerOptions{
 		Description: gitlab.String(d.Get("description").(string)),
@@ -121,7 +119,7 @@ func resourceGitlabPipelineTriggerUpdate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabPipelineTriggerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	log.Printf("[DEBUG] Delete gitlab PipelineTrigger %s", d.Id())
 
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -76,11 +79,34 @@ var resourceGitLabProjectSchema = map[string]*schema.Schema{
 		Computed:    true,
 	},
 	"import_url": {
-		Description: "Git URL to a repository to be imported.",
+		Description: "Git URL to a repository to be imported. Use `import_url_username` and `import_url_password` for the credentials, instead of adding them to the URL, so that they are treated as sensitive values, e.g. not shown in the plan.",
 		Type:        schema.TypeString,
 		Optional:    true,
 		ForceNew:    true,
 	},
+	"import_url_username": {
+		Description:  "The username for the `import_url`. Changing it updates the credentials of the import or mirror in place.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Sensitive:    true,
+		RequiredWith: []string{"import_url"},
+	},
+	"import_url_password": {
+		Description:  "The password for the `import_url`. Changing it updates the credentials of the import or mirror in place.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Sensitive:    true,
+		RequiredWith: []string{"import_url"},
+	},
+	"import_wait": {
+		Description: "Set to `false` to not wait for the import of the project to finish on creation, e.g. from `import_url`, a template or a fork. " +
+			"Defaults to `true`, i.e. the creation waits until the import has finished, so that resources which depend on the project find the imported repository, and fails with the import error if the import failed. " +
+			"The `default_branch` of a fork which isn't waited for is only set by the next apply, because its branches don't exist yet. " +
+			"This attribute is only used during resource creation and cannot be imported.",
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  true,
+	},
 	"request_access_enabled": {
 		Description: "Allow users to request member access.",
 		Type:        schema.TypeBool,
@@ -333,6 +359,34 @@ var resourceGitLabProjectSchema = map[string]*schema.Schema{
 		Type:        schema.TypeInt,
 		Optional:    true,
 	},
+	"forked_from_project_id": {
+		Description: "The ID of the project this project is forked from. When set on creation, the project is created as a fork of it, which waits for the repository to be copied. " +
+			"When changed on an existing project, its fork relationship is added, changed or, with `0`, removed, which requires administrator permissions. " +
+			"This option is mutually exclusive with `import_url`, `template_name` and `template_project_id`.",
+		Type:          schema.TypeInt,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"import_url", "template_name", "template_project_id"},
+	},
+	"fork_namespace": {
+		Description:   "The full path of the namespace the project is forked into, as an alternative to `namespace_id`. Only used when the project is created as a fork with `forked_from_project_id`.",
+		Type:          schema.TypeString,
+		Optional:      true,
+		ConflictsWith: []string{"namespace_id"},
+		RequiredWith:  []string{"forked_from_project_id"},
+	},
+	"mr_default_target_self": {
+		Description:  "Set to `true` to target the merge requests of the fork to itself instead of the forked project. Only used when the project is created as a fork with `forked_from_project_id`.",
+		Type:         schema.TypeBool,
+		Optional:     true,
+		RequiredWith: []string{"forked_from_project_id"},
+	},
+	"fork_branches": {
+		Description:  "The branches to fork, e.g. only the default branch. Defaults to all branches. The `default_branch` is only set if it's one of the forked branches. Only used when the project is created as a fork with `forked_from_project_id`.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		RequiredWith: []string{"forked_from_project_id"},
+	},
 	"pages_access_level": {
 		Description:  "Enable pages access control",
 		Type:         schema.TypeString,
@@ -396,6 +450,9 @@ var resourceGitLabProjectSchema = map[string]*schema.Schema{
 		Type:        schema.TypeBool,
 		Optional:    true,
 	},
+	"deletion_protection":           deletionProtectionSchema("project"),
+	"permanently_remove_on_destroy": permanentlyRemoveOnDestroySchema("project"),
+	"marked_for_deletion_at":        markedForDeletionSchema("project"),
 	"ci_forward_deployment_enabled": {
 		Description: "When a new deployment job starts, skip older deployment jobs that are still pending.",
 		Type:        schema.TypeBool,
@@ -684,12 +741,16 @@ This attribute is only used during resource creation, thus changes are suppresse
 					return true
 				},
 			},
-		}),
+		}, avatarSchema("project")),
 		CustomizeDiff: customdiff.All(
 			customdiff.ComputedIf("path_with_namespace", namespaceOrPathChanged),
 			customdiff.ComputedIf("ssh_url_to_repo", namespaceOrPathChanged),
 			customdiff.ComputedIf("http_url_to_repo", namespaceOrPathChanged),
 			customdiff.ComputedIf("web_url", namespaceOrPathChanged),
+			customizeDiffRequiresGitLab("push_rules", "", true),
+			customizeDiffAvatarHash,
+			customizeDiffImportURLCredentials,
+			customizeDiffRestoreMarkedForDeletion("marked_for_deletion_at"),
 		),
 	}
 })
@@ -716,9 +777,20 @@ func resourceGitlabProjectSetToState(ctx context.Context, client *gitlab.Client,
 	d.Set("only_allow_merge_if_all_discussions_are_resolved", project.OnlyAllowMergeIfAllDiscussionsAreResolved)
 	d.Set("allow_merge_on_skipped_pipeline", project.AllowMergeOnSkippedPipeline)
 	d.Set("namespace_id", project.Namespace.ID)
+	if project.ForkedFromProject != nil {
+		d.Set("forked_from_project_id", project.ForkedFromProject.ID)
+	} else {
+		d.Set("forked_from_project_id", 0)
+	}
 	d.Set("ssh_url_to_repo", project.SSHURLToRepo)
 	d.Set("http_url_to_repo", project.HTTPURLToRepo)
 	d.Set("web_url", project.WebURL)
+	d.Set("avatar_url", project.AvatarURL)
+	if project.MarkedForDeletionAt != nil {
+		d.Set("marked_for_deletion_at", project.MarkedForDeletionAt.String())
+	} else {
+		d.Set("marked_for_deletion_at", "")
+	}
 	d.Set("runners_token", project.RunnersToken)
 	d.Set("shared_runners_enabled", project.SharedRunnersEnabled)
 	if err := d.Set("tags", project.TagList); err != nil {
@@ -785,7 +857,7 @@ func resourceGitlabProjectSetToState(ctx context.Context, client *gitlab.Client,
 }
 
 func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	options := &gitlab.CreateProjectOptions{
 		Name:                             gitlab.String(d.Get("name").(string)),
@@ -842,8 +914,12 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 		options.InitializeWithReadme = gitlab.Bool(v.(bool))
 	}
 
-	if v, ok := d.GetOk("import_url"); ok {
-		options.ImportURL = gitlab.String(v.(string))
+	if _, ok := d.GetOk("import_url"); ok {
+		importURL, err := resourceGitlabProjectImportURL(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		options.ImportURL = importURL
 	}
 
 	if v, ok := d.GetOk("template_name"); ok {
@@ -998,24 +1074,35 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
-	log.Printf("[DEBUG] create gitlab project %q", *options.Name)
-
-	project, _, err := client.Projects.CreateProject(options, gitlab.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
+	var project *gitlab.Project
+	forkedFromProjectID, isFork := d.GetOk("forked_from_project_id")
+	if isFork {
+		log.Printf("[DEBUG] fork gitlab project %d as %q", forkedFromProjectID.(int), *options.Name)
+		fork, err := resourceGitlabProjectFork(ctx, client, d, forkedFromProjectID.(int), options)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		project = fork
+	} else {
+		log.Printf("[DEBUG] create gitlab project %q", *options.Name)
+		created, _, err := client.Projects.CreateProject(options, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		project = created
 	}
 
 	// from this point onwards no matter how we return, resource creation
 	// is committed to state since we set its ID
 	d.SetId(fmt.Sprintf("%d", project.ID))
 
-	// An import can be triggered by import_url or by creating the project from a template.
-	if project.ImportStatus != "none" {
+	// An import can be triggered by import_url, by creating the project from a template or by forking it.
+	if project.ImportStatus != "none" && d.Get("import_wait").(bool) {
 		log.Printf("[DEBUG] waiting for project %q import to finish", *options.Name)
 
 		stateConf := &resource.StateChangeConf{
 			Pending: []string{"scheduled", "started"},
-			Target:  []string{"finished"},
+			Target:  []string{"finished", "failed"},
 			Timeout: 10 * time.Minute,
 			Refresh: func() (interface{}, string, error) {
 				status, _, err := client.ProjectImportExport.ImportStatus(d.Id(), gitlab.WithContext(ctx))
@@ -1027,15 +1114,47 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 			},
 		}
 
-		if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		result, err := stateConf.WaitForStateContext(ctx)
+		if err != nil {
 			return diag.Errorf("error while waiting for project %q import to finish: %s", *options.Name, err)
 		}
+		if status := result.(*gitlab.ImportStatus); status.ImportStatus == "failed" {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("The import of project %q failed", *options.Name),
+				Detail:   fmt.Sprintf("GitLab reported the import error: %s", status.ImportError),
+			}}
+		}
 
 		// Read the project again, so that we can detect the default branch.
-		project, _, err = client.Projects.GetProject(project.ID, nil, gitlab.WithContext(ctx))
+		imported, _, err := client.Projects.GetProject(project.ID, nil, gitlab.WithContext(ctx))
 		if err != nil {
 			return diag.Errorf("Failed to get project %q after completing import: %s", d.Id(), err)
 		}
+		project = imported
+	}
+
+	if isFork {
+		// A fork is created with only a few of the attributes, thus the others are set with an update.
+		// It copies the settings of the forked project, thus all configured attributes and defaults are included.
+		editOptions, err := resourceGitlabProjectEditOptions(ctx, client, d, func(key string) bool {
+			// nolint:staticcheck // SA1019 ignore deprecated GetOkExists
+			// lintignore: XR001 // TODO: replace with alternative for GetOkExists
+			_, ok := d.GetOkExists(key)
+			return ok
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if editOptions.DefaultBranch != nil && !resourceGitlabProjectForkHasDefaultBranch(d, project) {
+			editOptions.DefaultBranch = nil
+		}
+		log.Printf("[DEBUG] update forked gitlab project %s", d.Id())
+		edited, _, err := client.Projects.EditProject(project.ID, editOptions, gitlab.WithContext(ctx))
+		if err != nil {
+			return diag.Errorf("Failed to update forked project %q: %s", d.Id(), err)
+		}
+		project = edited
 	}
 
 	if d.Get("archived").(bool) {
@@ -1123,10 +1242,11 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 	if v, ok := d.GetOkExists("skip_wait_for_default_branch_protection"); ok {
 		d.Set("skip_wait_for_default_branch_protection", v.(bool))
 	}
-	if !d.Get("skip_wait_for_default_branch_protection").(bool) {
+	// The branches of a fork are copied from the forked project, thus there is no new default branch to be protected.
+	if !d.Get("skip_wait_for_default_branch_protection").(bool) && !isFork {
 		// If the project is assigned to a group namespace and the group has *default branch protection*
 		// disabled (`default_branch_protection = 0`) then we don't have to wait for one.
-		waitForDefaultBranchProtection, err := expectDefaultBranchProtection(ctx, client, project)
+		waitForDefaultBranchProtection, err := expectDefaultBranchProtection(ctx, meta.(*providerMeta), project)
 		if err != nil {
 			return diag.Errorf("Failed to discover if branch protection is enabled by default or not for project %d: %+v", project.ID, err)
 		}
@@ -1169,14 +1289,14 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 	// lintignore: XR001 // TODO: replace with alternative for GetOkExists
 	if v, ok := d.GetOkExists("mirror_overwrites_diverged_branches"); ok {
 		editProjectOptions.MirrorOverwritesDivergedBranches = gitlab.Bool(v.(bool))
-		editProjectOptions.ImportURL = gitlab.String(d.Get("import_url").(string))
+		editProjectOptions.ImportURL = options.ImportURL
 	}
 
 	// nolint:staticcheck // SA1019 ignore deprecated GetOkExists
 	// lintignore: XR001 // TODO: replace with alternative for GetOkExists
 	if v, ok := d.GetOkExists("only_mirror_protected_branches"); ok {
 		editProjectOptions.OnlyMirrorProtectedBranches = gitlab.Bool(v.(bool))
-		editProjectOptions.ImportURL = gitlab.String(d.Get("import_url").(string))
+		editProjectOptions.ImportURL = options.ImportURL
 	}
 
 	if v, ok := d.GetOk("issues_template"); ok {
@@ -1215,26 +1335,29 @@ func resourceGitlabProjectCreate(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
+	if _, ok := d.GetOk("avatar"); ok {
+		if err := updateAvatar(ctx, client, d, fmt.Sprintf("projects/%s", d.Id())); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceGitlabProjectRead(ctx, d, meta)
 }
 
 func resourceGitlabProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	log.Printf("[DEBUG] read gitlab project %s", d.Id())
 
 	project, _, err := client.Projects.GetProject(d.Id(), nil, gitlab.WithContext(ctx))
+	// A project marked for deletion stays in the state, to restore it if it's still configured.
+	if removeGoneFromState(ctx, d, nil, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab project %s has already been deleted, removing from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
-	if project.MarkedForDeletionAt != nil {
-		log.Printf("[DEBUG] gitlab project %s is marked for deletion, removing from state", d.Id())
-		d.SetId("")
-		return nil
+	if isMarkedForDeletion(project) {
+		log.Printf("[DEBUG] gitlab project %s is marked for deletion, it's restored if it's still configured", d.Id())
 	}
 
 	if err := resourceGitlabProjectSetToState(ctx, client, d, project); err != nil {
@@ -1256,9 +1379,9 @@ func resourceGitlabProjectRead(ctx context.Context, d *schema.ResourceData, meta
 	return nil
 }
 
-func resourceGitlabProjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
-
+// resourceGitlabProjectEditOptions returns the options to update the project with the attributes for which include returns true,
+// e.g. d.HasChange. The `namespace_id` isn't included, because the project is transferred with a separate request.
+func resourceGitlabProjectEditOptions(ctx context.Context, client *gitlab.Client, d *schema.ResourceData, include func(key string) bool) (*gitlab.EditProjectOptions, error) {
 	// Always send the name field, to satisfy the requirement of having one
 	// of the project attributes listed below in the update call
 	// https://gitlab.com/gitlab-org/gitlab-foss/-/blob/master/lib/api/helpers/projects_helpers.rb#L120-188
@@ -1266,274 +1389,297 @@ func resourceGitlabProjectUpdate(ctx context.Context, d *schema.ResourceData, me
 		Name: gitlab.String(d.Get("name").(string)),
 	}
 
-	transferOptions := &gitlab.TransferProjectOptions{}
-
-	if d.HasChange("name") {
+	if include("name") {
 		options.Name = gitlab.String(d.Get("name").(string))
 	}
 
-	if d.HasChange("path") && (d.Get("path").(string) != "") {
+	if include("path") && (d.Get("path").(string) != "") {
 		options.Path = gitlab.String(d.Get("path").(string))
 	}
 
-	if d.HasChange("namespace_id") {
-		transferOptions.Namespace = gitlab.Int(d.Get("namespace_id").(int))
-	}
-
-	if d.HasChange("description") {
+	if include("description") {
 		options.Description = gitlab.String(d.Get("description").(string))
 	}
 
-	if d.HasChange("default_branch") {
+	if include("default_branch") {
 		options.DefaultBranch = gitlab.String(d.Get("default_branch").(string))
 	}
 
-	if d.HasChange("visibility_level") {
+	if include("visibility_level") {
 		options.Visibility = stringToVisibilityLevel(d.Get("visibility_level").(string))
 	}
 
-	if d.HasChange("merge_method") {
+	if include("merge_method") {
 		options.MergeMethod = stringToMergeMethod(d.Get("merge_method").(string))
 	}
 
-	if d.HasChange("only_allow_merge_if_pipeline_succeeds") {
+	if include("only_allow_merge_if_pipeline_succeeds") {
 		options.OnlyAllowMergeIfPipelineSucceeds = gitlab.Bool(d.Get("only_allow_merge_if_pipeline_succeeds").(bool))
 	}
 
-	if d.HasChange("only_allow_merge_if_all_discussions_are_resolved") {
+	if include("only_allow_merge_if_all_discussions_are_resolved") {
 		options.OnlyAllowMergeIfAllDiscussionsAreResolved = gitlab.Bool(d.Get("only_allow_merge_if_all_discussions_are_resolved").(bool))
 	}
 
-	if d.HasChange("allow_merge_on_skipped_pipeline") {
+	if include("allow_merge_on_skipped_pipeline") {
 		options.AllowMergeOnSkippedPipeline = gitlab.Bool(d.Get("allow_merge_on_skipped_pipeline").(bool))
 	}
 
-	if d.HasChange("request_access_enabled") {
+	if include("request_access_enabled") {
 		options.RequestAccessEnabled = gitlab.Bool(d.Get("request_access_enabled").(bool))
 	}
 
-	if d.HasChange("issues_enabled") {
+	if include("issues_enabled") {
 		options.IssuesEnabled = gitlab.Bool(d.Get("issues_enabled").(bool))
 	}
 
-	if d.HasChange("merge_requests_enabled") {
+	if include("merge_requests_enabled") {
 		options.MergeRequestsEnabled = gitlab.Bool(d.Get("merge_requests_enabled").(bool))
 	}
 
-	if d.HasChange("pipelines_enabled") {
+	if include("pipelines_enabled") {
 		options.JobsEnabled = gitlab.Bool(d.Get("pipelines_enabled").(bool))
 	}
 
-	if d.HasChange("approvals_before_merge") {
+	if include("approvals_before_merge") {
 		options.ApprovalsBeforeMerge = gitlab.Int(d.Get("approvals_before_merge").(int))
 	}
 
-	if d.HasChange("wiki_enabled") {
+	if include("wiki_enabled") {
 		options.WikiEnabled = gitlab.Bool(d.Get("wiki_enabled").(bool))
 	}
 
-	if d.HasChange("snippets_enabled") {
+	if include("snippets_enabled") {
 		options.SnippetsEnabled = gitlab.Bool(d.Get("snippets_enabled").(bool))
 	}
 
-	if d.HasChange("shared_runners_enabled") {
+	if include("shared_runners_enabled") {
 		options.SharedRunnersEnabled = gitlab.Bool(d.Get("shared_runners_enabled").(bool))
 	}
 
-	if d.HasChange("tags") {
+	if include("tags") {
 		options.TagList = stringSetToStringSlice(d.Get("tags").(*schema.Set))
 	}
 
-	if d.HasChange("container_registry_enabled") {
+	if include("container_registry_enabled") {
 		options.ContainerRegistryEnabled = gitlab.Bool(d.Get("container_registry_enabled").(bool))
 	}
 
-	if d.HasChange("lfs_enabled") {
+	if include("lfs_enabled") {
 		options.LFSEnabled = gitlab.Bool(d.Get("lfs_enabled").(bool))
 	}
 
 	if supportsSquashOption, err := isGitLabVersionAtLeast(ctx, client, "14.1")(); err != nil {
-		return diag.FromErr(err)
-	} else if supportsSquashOption && d.HasChange("squash_option") {
+		return nil, err
+	} else if supportsSquashOption && include("squash_option") {
 		options.SquashOption = stringToSquashOptionValue(d.Get("squash_option").(string))
 	}
 
-	if d.HasChange("remove_source_branch_after_merge") {
+	if include("remove_source_branch_after_merge") {
 		options.RemoveSourceBranchAfterMerge = gitlab.Bool(d.Get("remove_source_branch_after_merge").(bool))
 	}
 
-	if d.HasChange("printing_merge_request_link_enabled") {
+	if include("printing_merge_request_link_enabled") {
 		options.PrintingMergeRequestLinkEnabled = gitlab.Bool(d.Get("printing_merge_request_link_enabled").(bool))
 	}
 
-	if d.HasChange("packages_enabled") {
+	if include("packages_enabled") {
 		options.PackagesEnabled = gitlab.Bool(d.Get("packages_enabled").(bool))
 	}
 
-	if d.HasChange("pages_access_level") {
+	if include("pages_access_level") {
 		options.PagesAccessLevel = stringToAccessControlValue(d.Get("pages_access_level").(string))
 	}
 
-	if d.HasChange("mirror") {
-		options.ImportURL = gitlab.String(d.Get("import_url").(string))
+	if include("mirror") {
 		options.Mirror = gitlab.Bool(d.Get("mirror").(bool))
 	}
 
-	if d.HasChange("mirror_trigger_builds") {
-		options.ImportURL = gitlab.String(d.Get("import_url").(string))
+	if include("mirror_trigger_builds") {
 		options.MirrorTriggerBuilds = gitlab.Bool(d.Get("mirror_trigger_builds").(bool))
 	}
 
-	if d.HasChange("only_mirror_protected_branches") {
-		options.ImportURL = gitlab.String(d.Get("import_url").(string))
+	if include("only_mirror_protected_branches") {
 		options.OnlyMirrorProtectedBranches = gitlab.Bool(d.Get("only_mirror_protected_branches").(bool))
 	}
 
-	if d.HasChange("mirror_overwrites_diverged_branches") {
-		options.ImportURL = gitlab.String(d.Get("import_url").(string))
+	if include("mirror_overwrites_diverged_branches") {
 		options.MirrorOverwritesDivergedBranches = gitlab.Bool(d.Get("mirror_overwrites_diverged_branches").(bool))
 	}
 
-	if d.HasChange("build_coverage_regex") {
+	// The mirror options require the import URL, which is sent with its credentials, so that they are kept.
+	if d.Get("import_url").(string) != "" && (include("import_url_username") || include("import_url_password") || include("mirror") || include("mirror_trigger_builds") || include("only_mirror_protected_branches") || include("mirror_overwrites_diverged_branches")) {
+		importURL, err := resourceGitlabProjectImportURL(d)
+		if err != nil {
+			return nil, err
+		}
+		options.ImportURL = importURL
+	}
+
+	if include("build_coverage_regex") {
 		options.IssuesTemplate = gitlab.String(d.Get("build_coverage_regex").(string))
 	}
 
-	if d.HasChange("issues_template") {
+	if include("issues_template") {
 		options.IssuesTemplate = gitlab.String(d.Get("issues_template").(string))
 	}
 
-	if d.HasChange("merge_requests_template") {
+	if include("merge_requests_template") {
 		options.MergeRequestsTemplate = gitlab.String(d.Get("merge_requests_template").(string))
 	}
 
-	if d.HasChange("ci_config_path") {
+	if include("ci_config_path") {
 		options.CIConfigPath = gitlab.String(d.Get("ci_config_path").(string))
 	}
 
-	if d.HasChange("ci_forward_deployment_enabled") {
+	if include("ci_forward_deployment_enabled") {
 		options.CIForwardDeploymentEnabled = gitlab.Bool(d.Get("ci_forward_deployment_enabled").(bool))
 	}
 
-	if d.HasChange("merge_pipelines_enabled") {
+	if include("merge_pipelines_enabled") {
 		options.MergePipelinesEnabled = gitlab.Bool(d.Get("merge_pipelines_enabled").(bool))
 	}
 
-	if d.HasChange("merge_trains_enabled") {
+	if include("merge_trains_enabled") {
 		options.MergeTrainsEnabled = gitlab.Bool(d.Get("merge_trains_enabled").(bool))
 	}
 
-	if d.HasChange("resolve_outdated_diff_discussions") {
+	if include("resolve_outdated_diff_discussions") {
 		options.ResolveOutdatedDiffDiscussions = gitlab.Bool(d.Get("resolve_outdated_diff_discussions").(bool))
 	}
 
-	if d.HasChange("analytics_access_level") {
+	if include("analytics_access_level") {
 		options.AnalyticsAccessLevel = stringToAccessControlValue(d.Get("analytics_access_level").(string))
 	}
 
-	if d.HasChange("auto_cancel_pending_pipelines") {
+	if include("auto_cancel_pending_pipelines") {
 		options.AutoCancelPendingPipelines = gitlab.String(d.Get("auto_cancel_pending_pipelines").(string))
 	}
 
-	if d.HasChange("auto_devops_deploy_strategy") {
+	if include("auto_devops_deploy_strategy") {
 		options.AutoDevopsDeployStrategy = gitlab.String(d.Get("auto_devops_deploy_strategy").(string))
 	}
 
-	if d.HasChange("auto_devops_enabled") {
+	if include("auto_devops_enabled") {
 		options.AutoDevopsEnabled = gitlab.Bool(d.Get("auto_devops_enabled").(bool))
 	}
 
-	if d.HasChange("autoclose_referenced_issues") {
+	if include("autoclose_referenced_issues") {
 		options.AutocloseReferencedIssues = gitlab.Bool(d.Get("autoclose_referenced_issues").(bool))
 	}
 
-	if d.HasChange("build_git_strategy") {
+	if include("build_git_strategy") {
 		options.BuildGitStrategy = gitlab.String(d.Get("build_git_strategy").(string))
 	}
 
-	if d.HasChange("build_timeout") {
+	if include("build_timeout") {
 		options.BuildTimeout = gitlab.Int(d.Get("build_timeout").(int))
 	}
 
-	if d.HasChange("builds_access_level") {
+	if include("builds_access_level") {
 		options.BuildsAccessLevel = stringToAccessControlValue(d.Get("builds_access_level").(string))
 	}
 
-	if d.HasChange("container_expiration_policy") {
+	if include("container_expiration_policy") {
 		options.ContainerExpirationPolicyAttributes = expandContainerExpirationPolicyAttributes(d)
 	}
 
-	if d.HasChange("container_registry_access_level") {
+	if include("container_registry_access_level") {
 		options.ContainerRegistryAccessLevel = stringToAccessControlValue(d.Get("container_registry_access_level").(string))
 	}
 
-	if d.HasChange("emails_disabled") {
+	if include("emails_disabled") {
 		options.EmailsDisabled = gitlab.Bool(d.Get("emails_disabled").(bool))
 	}
 
-	if d.HasChange("external_authorization_classification_label") {
+	if include("external_authorization_classification_label") {
 		options.ExternalAuthorizationClassificationLabel = gitlab.String(d.Get("external_authorization_classification_label").(string))
 	}
 
-	if d.HasChange("forking_access_level") {
+	if include("forking_access_level") {
 		options.ForkingAccessLevel = stringToAccessControlValue(d.Get("forking_access_level").(string))
 	}
 
-	if d.HasChange("issues_access_level") {
+	if include("issues_access_level") {
 		options.IssuesAccessLevel = stringToAccessControlValue(d.Get("issues_access_level").(string))
 	}
 
-	if d.HasChange("merge_requests_access_level") {
+	if include("merge_requests_access_level") {
 		options.MergeRequestsAccessLevel = stringToAccessControlValue(d.Get("merge_requests_access_level").(string))
 	}
 
-	if d.HasChange("operations_access_level") {
+	if include("operations_access_level") {
 		options.OperationsAccessLevel = stringToAccessControlValue(d.Get("operations_access_level").(string))
 	}
 
-	if d.HasChange("public_builds") {
+	if include("public_builds") {
 		options.PublicBuilds = gitlab.Bool(d.Get("public_builds").(bool))
 	}
 
-	if d.HasChange("repository_access_level") {
+	if include("repository_access_level") {
 		options.RepositoryAccessLevel = stringToAccessControlValue(d.Get("repository_access_level").(string))
 	}
 
-	if d.HasChange("repository_storage") {
+	if include("repository_storage") {
 		options.RepositoryStorage = gitlab.String(d.Get("repository_storage").(string))
 	}
 
-	if d.HasChange("requirements_access_level") {
+	if include("requirements_access_level") {
 		options.RequirementsAccessLevel = stringToAccessControlValue(d.Get("requirements_access_level").(string))
 	}
 
-	if d.HasChange("security_and_compliance_access_level") {
+	if include("security_and_compliance_access_level") {
 		options.SecurityAndComplianceAccessLevel = stringToAccessControlValue(d.Get("security_and_compliance_access_level").(string))
 	}
 
-	if d.HasChange("snippets_access_level") {
+	if include("snippets_access_level") {
 		options.SnippetsAccessLevel = stringToAccessControlValue(d.Get("snippets_access_level").(string))
 	}
 
-	if d.HasChange("topics") {
+	if include("topics") {
 		options.Topics = stringSetToStringSlice(d.Get("topics").(*schema.Set))
 	}
 
-	if d.HasChange("wiki_access_level") {
+	if include("wiki_access_level") {
 		options.WikiAccessLevel = stringToAccessControlValue(d.Get("wiki_access_level").(string))
 	}
 
-	if d.HasChange("squash_commit_template") {
+	if include("squash_commit_template") {
 		options.SquashCommitTemplate = gitlab.String(d.Get("squash_commit_template").(string))
 	}
 
-	if d.HasChange("merge_commit_template") {
+	if include("merge_commit_template") {
 		options.MergeCommitTemplate = gitlab.String(d.Get("merge_commit_template").(string))
 	}
 
-	if d.HasChange("ci_default_git_depth") {
+	if include("ci_default_git_depth") {
 		options.CIDefaultGitDepth = gitlab.Int(d.Get("ci_default_git_depth").(int))
 	}
 
+	return options, nil
+}
+
+func resourceGitlabProjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	options, err := resourceGitlabProjectEditOptions(ctx, client, d, d.HasChange)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	transferOptions := &gitlab.TransferProjectOptions{}
+
+	if d.HasChange("namespace_id") {
+		transferOptions.Namespace = gitlab.Int(d.Get("namespace_id").(int))
+	}
+
+	if oldMarkedForDeletionAt, _ := d.GetChange("marked_for_deletion_at"); oldMarkedForDeletionAt.(string) != "" {
+		log.Printf("[DEBUG] restore gitlab project %s, because it's marked for deletion", d.Id())
+		if err := restoreProject(ctx, client, d.Id()); err != nil {
+			return diag.Errorf("Failed to restore project %q, which is marked for deletion: %s", d.Id(), err)
+		}
+	}
+
 	if *options != (gitlab.EditProjectOptions{}) {
 		log.Printf("[DEBUG] update gitlab project %s", d.Id())
 		_, _, err := client.Projects.EditProject(d.Id(), options, gitlab.WithContext(ctx))
@@ -1544,10 +1690,30 @@ func resourceGitlabProjectUpdate(ctx context.Context, d *schema.ResourceData, me
 
 	if *transferOptions != (gitlab.TransferProjectOptions{}) {
 		log.Printf("[DEBUG] transferring project %s to namespace %d", d.Id(), transferOptions.Namespace)
-		_, _, err := client.Projects.TransferProject(d.Id(), transferOptions, gitlab.WithContext(ctx))
+		project, _, err := client.Projects.TransferProject(d.Id(), transferOptions, gitlab.WithContext(ctx))
 		if err != nil {
 			return diag.FromErr(err)
 		}
+		namespaceID := d.Get("namespace_id").(int)
+		if err := resourceGitlabProjectWaitForTransfer(ctx, client, project, namespaceID); err != nil {
+			return diag.Errorf("error while waiting for project %q to be transferred to namespace %d: %s", d.Id(), namespaceID, err)
+		}
+	}
+
+	if d.HasChange("forked_from_project_id") {
+		oldForkedFromProjectID, newForkedFromProjectID := d.GetChange("forked_from_project_id")
+		if oldForkedFromProjectID.(int) != 0 {
+			log.Printf("[DEBUG] remove fork relationship of project %s to project %d", d.Id(), oldForkedFromProjectID.(int))
+			if _, err := client.Projects.DeleteProjectForkRelation(d.Id(), gitlab.WithContext(ctx)); err != nil {
+				return diag.Errorf("Failed to remove the fork relationship of project %q: %s", d.Id(), err)
+			}
+		}
+		if newForkedFromProjectID.(int) != 0 {
+			log.Printf("[DEBUG] add fork relationship of project %s to project %d", d.Id(), newForkedFromProjectID.(int))
+			if _, _, err := client.Projects.CreateProjectForkRelation(d.Id(), newForkedFromProjectID.(int), gitlab.WithContext(ctx)); err != nil {
+				return diag.Errorf("Failed to add the fork relationship of project %q: %s", d.Id(), err)
+			}
+		}
 	}
 
 	if d.HasChange("archived") {
@@ -1573,18 +1739,47 @@ func resourceGitlabProjectUpdate(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
+	if avatarChanged(d) {
+		if err := updateAvatar(ctx, client, d, fmt.Sprintf("projects/%s", d.Id())); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceGitlabProjectRead(ctx, d, meta)
 }
 
 func resourceGitlabProjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
+
+	if diags := checkDeletionProtection(d, "gitlab_project"); diags != nil {
+		return diags
+	}
 
 	if !d.Get("archive_on_destroy").(bool) {
 		log.Printf("[DEBUG] Delete gitlab project %s", d.Id())
-		_, err := client.Projects.DeleteProject(d.Id(), gitlab.WithContext(ctx))
+		_, deleteErr := client.Projects.DeleteProject(d.Id(), gitlab.WithContext(ctx))
+		// The delete is rejected with a 400 if the project has already been marked for deletion.
+		if deleteErr != nil && !is400(deleteErr) {
+			return diag.FromErr(deleteErr)
+		}
+
+		project, _, err := client.Projects.GetProject(d.Id(), nil, gitlab.WithContext(ctx))
 		if err != nil {
+			if is404(err) {
+				return nil
+			}
 			return diag.FromErr(err)
 		}
+		permanentlyRemoveOnDestroy := d.Get("permanently_remove_on_destroy").(bool)
+		if !isMarkedForDeletion(project) {
+			if deleteErr != nil {
+				return diag.FromErr(deleteErr)
+			}
+		} else if permanentlyRemoveOnDestroy {
+			if err := permanentlyRemove(ctx, client, fmt.Sprintf("projects/%d", project.ID), project.PathWithNamespace); err != nil {
+				return diag.FromErr(err)
+			}
+		}
 
 		// Wait for the project to be deleted.
 		// Deleting a project in gitlab is async.
@@ -1600,7 +1795,7 @@ func resourceGitlabProjectDelete(ctx context.Context, d *schema.ResourceData, me
 					log.Printf("[ERROR] Received error: %#v", err)
 					return out, "Error", err
 				}
-				if out.MarkedForDeletionAt != nil {
+				if isMarkedForDeletion(out) && !permanentlyRemoveOnDestroy {
 					// Represents a Gitlab EE soft-delete
 					return out, "Deleted", nil
 				}
@@ -1628,6 +1823,100 @@ func resourceGitlabProjectDelete(ctx context.Context, d *schema.ResourceData, me
 	return nil
 }
 
+// resourceGitlabProjectImportURL returns the `import_url` with the credentials of `import_url_username` and `import_url_password`.
+func resourceGitlabProjectImportURL(d *schema.ResourceData) (*string, error) {
+	importURL, err := importURLWithCredentials(d.Get("import_url").(string), d.Get("import_url_username").(string), d.Get("import_url_password").(string))
+	if err != nil {
+		return nil, err
+	}
+	return gitlab.String(importURL), nil
+}
+
+// customizeDiffImportURLCredentials checks the `import_url` and its credentials during the plan,
+// instead of failing when the import URL is built on create or update.
+func customizeDiffImportURLCredentials(ctx context.Context, rd *schema.ResourceDiff, meta interface{}) error {
+	_, err := importURLWithCredentials(rd.Get("import_url").(string), rd.Get("import_url_username").(string), rd.Get("import_url_password").(string))
+	return err
+}
+
+func importURLWithCredentials(importURL string, username string, password string) (string, error) {
+	if username == "" && password == "" {
+		return importURL, nil
+	}
+
+	u, err := url.Parse(importURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid import_url: %w", err)
+	}
+	if u.User != nil {
+		return "", fmt.Errorf("the import_url must not contain credentials if import_url_username or import_url_password are set")
+	}
+	u.User = url.User(username)
+	if password != "" {
+		u.User = url.UserPassword(username, password)
+	}
+	return u.String(), nil
+}
+
+// resourceGitlabProjectForkOptions adds the `branches` parameter to the fork options, which go-gitlab doesn't support yet.
+type resourceGitlabProjectForkOptions struct {
+	gitlab.ForkProjectOptions
+	Branches *string `url:"branches,omitempty" json:"branches,omitempty"`
+}
+
+// resourceGitlabProjectFork forks the given project with the attributes supported by the fork API.
+// The other attributes of the given create options must be set with an update once the fork is ready.
+func resourceGitlabProjectFork(ctx context.Context, client *gitlab.Client, d *schema.ResourceData, forkedFromProjectID int, options *gitlab.CreateProjectOptions) (*gitlab.Project, error) {
+	forkOptions := resourceGitlabProjectForkOptions{
+		ForkProjectOptions: gitlab.ForkProjectOptions{
+			Name:        options.Name,
+			Path:        options.Path,
+			Description: options.Description,
+			NamespaceID: options.NamespaceID,
+			Visibility:  options.Visibility,
+		},
+	}
+	if v, ok := d.GetOk("fork_namespace"); ok {
+		forkOptions.NamespacePath = gitlab.String(v.(string))
+	}
+	if v, ok := d.GetOk("mr_default_target_self"); ok {
+		forkOptions.MergeRequestDefaultTargetSelf = gitlab.Bool(v.(bool))
+	}
+	if v, ok := d.GetOk("fork_branches"); ok {
+		forkOptions.Branches = gitlab.String(v.(string))
+	}
+
+	req, err := client.NewRequest(http.MethodPost, fmt.Sprintf("projects/%d/fork", forkedFromProjectID), &forkOptions, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	project := new(gitlab.Project)
+	if _, err := client.Do(req, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// resourceGitlabProjectForkHasDefaultBranch checks if the configured default branch can be set on the given fork:
+// its repository must have been copied, see `import_wait`, and the branch must be one of the `fork_branches`.
+func resourceGitlabProjectForkHasDefaultBranch(d *schema.ResourceData, fork *gitlab.Project) bool {
+	defaultBranch := d.Get("default_branch").(string)
+	if fork.ImportStatus != "finished" && fork.ImportStatus != "none" {
+		log.Printf("[WARN] the default branch %s of project %s is not set, because its repository is still being forked", defaultBranch, d.Id())
+		return false
+	}
+	if v, ok := d.GetOk("fork_branches"); ok {
+		for _, branch := range strings.Split(v.(string), ",") {
+			if strings.TrimSpace(branch) == defaultBranch {
+				return true
+			}
+		}
+		log.Printf("[WARN] the default branch %s of project %s is not set, because it's not one of the forked branches", defaultBranch, d.Id())
+		return false
+	}
+	return true
+}
+
 func editOrAddPushRules(ctx context.Context, client *gitlab.Client, projectID string, d *schema.ResourceData) error {
 	log.Printf("[DEBUG] Editing push rules for project %q", projectID)
 
@@ -1834,11 +2123,45 @@ func expandContainerExpirationPolicyAttributes(d *schema.ResourceData) *gitlab.C
 	return &policy
 }
 
+// resourceGitlabProjectWaitForTransfer waits until the given project is visible with its `path_with_namespace`
+// in the given namespace, because other resources may use it right after the transfer.
+func resourceGitlabProjectWaitForTransfer(ctx context.Context, client *gitlab.Client, project *gitlab.Project, namespaceID int) error {
+	namespace, _, err := client.Namespaces.GetNamespace(namespaceID, gitlab.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	pathWithNamespace := namespace.FullPath + "/" + project.Path
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"transferring"},
+		Target:  []string{"transferred"},
+		Refresh: func() (interface{}, string, error) {
+			out, _, err := client.Projects.GetProject(project.ID, nil, gitlab.WithContext(ctx))
+			if err != nil {
+				return nil, "", err
+			}
+			if out.PathWithNamespace != pathWithNamespace {
+				log.Printf("[DEBUG] project %d is still available at %s instead of %s", project.ID, out.PathWithNamespace, pathWithNamespace)
+				return out, "transferring", nil
+			}
+			return out, "transferred", nil
+		},
+		Timeout:    5 * time.Minute,
+		MinTimeout: 1 * time.Second,
+	}
+	_, err = stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+// namespaceOrPathChanged checks if the project is moved, which is done in-place by transferring or renaming it,
+// thus only the attributes which contain its full path are unknown until the update is applied.
 func namespaceOrPathChanged(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
 	return d.HasChange("namespace_id") || d.HasChange("path")
 }
 
-func expectDefaultBranchProtection(ctx context.Context, client *gitlab.Client, project *gitlab.Project) (bool, error) {
+func expectDefaultBranchProtection(ctx context.Context, meta *providerMeta, project *gitlab.Project) (bool, error) {
+	client := meta.client
+
 	// If the project is part of a group it may have default branch protection disabled for its projects
 	if project.Namespace.Kind == "group" {
 		group, _, err := client.Groups.GetGroup(project.Namespace.ID, nil, gitlab.WithContext(ctx))
@@ -1849,7 +2172,7 @@ func expectDefaultBranchProtection(ctx context.Context, client *gitlab.Client, p
 		return group.DefaultBranchProtection != 0, nil
 	}
 
-	isAdmin, err := isCurrentUserAdmin(ctx, client)
+	isAdmin, err := meta.isCurrentUserAdmin(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to check if user is admin to verify is default branch protection is enabled on instance-level: %w", err)
 	}
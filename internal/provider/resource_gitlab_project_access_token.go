@@ -97,7 +97,7 @@ var _ = registerResource("gitlab_project_access_token", func() *schema.Resource
 })
 
 func resourceGitlabProjectAccessTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	accessLevelId := accessLevelNameToValue[d.Get("access_level").(string)]
 	project := d.Get("project").(string)
 
@@ -136,7 +136,7 @@ func resourceGitlabProjectAccessTokenRead(ctx context.Context, d *schema.Resourc
 		return diag.Errorf("Error parsing ID: %s", d.Id())
 	}
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	projectAccessTokenID, err := strconv.Atoi(PATstring)
 	if err != nil {
@@ -146,12 +146,10 @@ func resourceGitlabProjectAccessTokenRead(ctx context.Context, d *schema.Resourc
 	log.Printf("[DEBUG] read gitlab ProjectAccessToken %d, project ID %s", projectAccessTokenID, project)
 
 	projectAccessToken, _, err := client.ProjectAccessTokens.GetProjectAccessToken(project, projectAccessTokenID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, projectAccessToken, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] GitLab ProjectAccessToken %d, project ID %s not found, removing from state", projectAccessTokenID, project)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -178,7 +176,7 @@ func resourceGitlabProjectAccessTokenDelete(ctx context.Context, d *schema.Resou
 		return diag.Errorf("Error parsing ID: %s", d.Id())
 	}
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	projectAccessTokenID, err := strconv.Atoi(patString)
 	if err != nil {
@@ -98,7 +98,15 @@ func resourceGitlabProjectApprovalRuleCreate(ctx context.Context, d *schema.Reso
 
 	log.Printf("[DEBUG] Project %s create gitlab project-level rule %+v", project, options)
 
-	client := meta.(*gitlab.Client)
+	// Changes to the approval rules of a project conflict with each other when made concurrently.
+	locks := meta.(*providerMeta).locks
+	key := lockKey("project", project, "approval_rules")
+	if err := locks.lock(ctx, key); err != nil {
+		return diag.FromErr(err)
+	}
+	defer locks.unlock(key)
+
+	client := meta.(*providerMeta).client
 
 	rule, _, err := client.Projects.CreateProjectApprovalRule(project, &options, gitlab.WithContext(ctx))
 	if err != nil {
@@ -124,15 +132,13 @@ func resourceGitlabProjectApprovalRuleRead(ctx context.Context, d *schema.Resour
 		return diag.FromErr(err)
 	}
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	rule, _, err := client.Projects.GetProjectApprovalRule(projectID, ruleID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, rule, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] no project-level rule %s found, removing from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -177,7 +183,15 @@ func resourceGitlabProjectApprovalRuleUpdate(ctx context.Context, d *schema.Reso
 
 	log.Printf("[DEBUG] Project %s update gitlab project-level approval rule %s", projectID, *options.Name)
 
-	client := meta.(*gitlab.Client)
+	// Changes to the approval rules of a project conflict with each other when made concurrently.
+	locks := meta.(*providerMeta).locks
+	key := lockKey("project", projectID, "approval_rules")
+	if err := locks.lock(ctx, key); err != nil {
+		return diag.FromErr(err)
+	}
+	defer locks.unlock(key)
+
+	client := meta.(*providerMeta).client
 
 	_, _, err = client.Projects.UpdateProjectApprovalRule(projectID, ruleIDInt, &options, gitlab.WithContext(ctx))
 	if err != nil {
@@ -200,7 +214,15 @@ func resourceGitlabProjectApprovalRuleDelete(ctx context.Context, d *schema.Reso
 
 	log.Printf("[DEBUG] Project %s delete gitlab project-level approval rule %d", project, ruleIDInt)
 
-	client := meta.(*gitlab.Client)
+	// Changes to the approval rules of a project conflict with each other when made concurrently.
+	locks := meta.(*providerMeta).locks
+	key := lockKey("project", project, "approval_rules")
+	if err := locks.lock(ctx, key); err != nil {
+		return diag.FromErr(err)
+	}
+	defer locks.unlock(key)
+
+	client := meta.(*providerMeta).client
 
 	_, err = client.Projects.DeleteProjectApprovalRule(project, ruleIDInt, gitlab.WithContext(ctx))
 	if err != nil {
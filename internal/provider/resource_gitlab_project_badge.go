@@ -62,7 +62,7 @@ var _ = registerResource("gitlab_project_badge", func() *schema.Resource {
 })
 
 func resourceGitlabProjectBadgeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectID := d.Get("project").(string)
 	options := &gitlab.AddProjectBadgeOptions{
 		LinkURL:  gitlab.String(d.Get("link_url").(string)),
@@ -85,7 +85,7 @@ func resourceGitlabProjectBadgeCreate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabProjectBadgeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectID, badgeID, err := resourceGitlabProjectBadgeParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -94,12 +94,10 @@ func resourceGitlabProjectBadgeRead(ctx context.Context, d *schema.ResourceData,
 	log.Printf("[DEBUG] read gitlab project badge %s/%d", projectID, badgeID)
 
 	badge, _, err := client.ProjectBadges.GetProjectBadge(projectID, badgeID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, badge, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] project badge %d in project %s doesn't exist anymore, removing from state", badgeID, projectID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -108,7 +106,7 @@ func resourceGitlabProjectBadgeRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabProjectBadgeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectID, badgeID, err := resourceGitlabProjectBadgeParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -131,7 +129,7 @@ func resourceGitlabProjectBadgeUpdate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabProjectBadgeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectID, badgeID, err := resourceGitlabProjectBadgeParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -128,7 +128,7 @@ var _ = registerResource("gitlab_project_cluster", func() *schema.Resource {
 })
 
 func resourceGitlabProjectClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	pk := gitlab.AddPlatformKubernetesOptions{
@@ -182,7 +182,7 @@ func resourceGitlabProjectClusterCreate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceGitlabProjectClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project, clusterId, err := projectIdAndClusterIdFromId(d.Id())
 	if err != nil {
@@ -192,12 +192,10 @@ func resourceGitlabProjectClusterRead(ctx context.Context, d *schema.ResourceDat
 	log.Printf("[DEBUG] read gitlab project cluster %q/%d", project, clusterId)
 
 	cluster, _, err := client.ProjectCluster.GetCluster(project, clusterId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, cluster, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab project cluster not found %s/%d", project, clusterId)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -225,7 +223,7 @@ func resourceGitlabProjectClusterRead(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabProjectClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project, clusterId, err := projectIdAndClusterIdFromId(d.Id())
 	if err != nil {
@@ -284,7 +282,7 @@ func resourceGitlabProjectClusterUpdate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceGitlabProjectClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, clusterId, err := projectIdAndClusterIdFromId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -94,7 +94,7 @@ func resourceGitlabProjectEnvironmentCreate(ctx context.Context, d *schema.Resou
 
 	log.Printf("[DEBUG] Project %s create gitlab environment %q", project, *options.Name)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	environment, _, err := client.Environments.CreateEnvironment(project, &options, gitlab.WithContext(ctx))
 	if err != nil {
@@ -119,15 +119,13 @@ func resourceGitlabProjectEnvironmentRead(ctx context.Context, d *schema.Resourc
 
 	log.Printf("[DEBUG] Project %s read gitlab environment %d", project, environmentID)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	environment, _, err := client.Environments.GetEnvironment(project, environmentID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, environment, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] Project %s gitlab environment %d not found, removing from state", project, environmentID)
-			d.SetId("")
-			return nil
-		}
 		return diag.Errorf("error getting gitlab project %s environment %d: %v", project, environmentID, err)
 	}
 
@@ -161,7 +159,7 @@ func resourceGitlabProjectEnvironmentUpdate(ctx context.Context, d *schema.Resou
 
 	log.Printf("[DEBUG] Project %s update gitlab environment %d", project, environmentID)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	if _, _, err := client.Environments.EditEnvironment(project, environmentID, options, gitlab.WithContext(ctx)); err != nil {
 		return diag.Errorf("error editing gitlab project %s environment %d: %v", project, environmentID, err)
@@ -171,7 +169,7 @@ func resourceGitlabProjectEnvironmentUpdate(ctx context.Context, d *schema.Resou
 }
 
 func resourceGitlabProjectEnvironmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, environmentID, err := resourceGitlabProjectEnvironmentParseID(d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -187,12 +185,10 @@ func resourceGitlabProjectEnvironmentDelete(ctx context.Context, d *schema.Resou
 		}
 	} else {
 		environment, _, err := client.Environments.GetEnvironment(project, environmentID, gitlab.WithContext(ctx))
+		if removeGoneFromState(ctx, d, environment, err) {
+			return nil
+		}
 		if err != nil {
-			if is404(err) {
-				log.Printf("[DEBUG] Project %s gitlab environment %d not found, removing from state", project, environmentID)
-				d.SetId("")
-				return nil
-			}
 			return diag.Errorf("error getting gitlab project %s environment %d: %v", project, environmentID, err)
 		}
 
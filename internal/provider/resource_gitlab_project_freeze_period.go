@@ -62,7 +62,7 @@ func resourceGitlabProjectFreezePeriodCreate(ctx context.Context, d *schema.Reso
 
 	log.Printf("[DEBUG] Project %s create gitlab project-level freeze period %+v", projectID, options)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	FreezePeriod, _, err := client.FreezePeriods.CreateFreezePeriodOptions(projectID, &options, gitlab.WithContext(ctx))
 	if err != nil {
 		return diag.FromErr(err)
@@ -75,7 +75,7 @@ func resourceGitlabProjectFreezePeriodCreate(ctx context.Context, d *schema.Reso
 }
 
 func resourceGitlabProjectFreezePeriodRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectID, freezePeriodID, err := projectIDAndFreezePeriodIDFromID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -84,12 +84,10 @@ func resourceGitlabProjectFreezePeriodRead(ctx context.Context, d *schema.Resour
 	log.Printf("[DEBUG] read gitlab FreezePeriod %s/%d", projectID, freezePeriodID)
 
 	freezePeriod, _, err := client.FreezePeriods.GetFreezePeriod(projectID, freezePeriodID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, freezePeriod, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] project freeze period for %s not found so removing it from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -102,7 +100,7 @@ func resourceGitlabProjectFreezePeriodRead(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabProjectFreezePeriodUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectID, freezePeriodID, err := projectIDAndFreezePeriodIDFromID(d.Id())
 	options := &gitlab.UpdateFreezePeriodOptions{}
 
@@ -133,7 +131,7 @@ func resourceGitlabProjectFreezePeriodUpdate(ctx context.Context, d *schema.Reso
 }
 
 func resourceGitlabProjectFreezePeriodDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectID, freezePeriodID, err := projectIDAndFreezePeriodIDFromID(d.Id())
 	log.Printf("[DEBUG] Delete gitlab FreezePeriod %s", d.Id())
 
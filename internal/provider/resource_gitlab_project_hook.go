@@ -30,7 +30,7 @@ var _ = registerResource("gitlab_project_hook", func() *schema.Resource {
 })
 
 func resourceGitlabProjectHookCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	options := &gitlab.AddProjectHookOptions{
 		URL:                      gitlab.String(d.Get("url").(string)),
@@ -68,7 +68,7 @@ func resourceGitlabProjectHookCreate(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabProjectHookRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	hookId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -77,12 +77,10 @@ func resourceGitlabProjectHookRead(ctx context.Context, d *schema.ResourceData,
 	log.Printf("[DEBUG] read gitlab project hook %s/%d", project, hookId)
 
 	hook, _, err := client.Projects.GetProjectHook(project, hookId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, hook, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab project hook not found %s/%d, removing from state", project, hookId)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -94,7 +92,7 @@ func resourceGitlabProjectHookRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabProjectHookUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	hookId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -133,7 +131,7 @@ func resourceGitlabProjectHookUpdate(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabProjectHookDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	hookId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -51,7 +51,7 @@ var _ = registerResource("gitlab_project_issue", func() *schema.Resource {
 })
 
 func resourceGitlabProjectIssueCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	options := &gitlab.CreateIssueOptions{
@@ -127,19 +127,17 @@ func resourceGitlabProjectIssueCreate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabProjectIssueRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, issueIID, err := resourceGitLabProjectIssueParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	issue, _, err := client.Issues.GetIssue(project, issueIID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, issue, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[WARN] issue %d in project %s not found, removing from state", issueIID, project)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -151,7 +149,7 @@ func resourceGitlabProjectIssueRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabProjectIssueUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, issueIID, err := resourceGitLabProjectIssueParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -212,7 +210,7 @@ func resourceGitlabProjectIssueUpdate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabProjectIssueDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, issueIID, err := resourceGitLabProjectIssueParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -32,7 +32,7 @@ var _ = registerResource("gitlab_project_issue_board", func() *schema.Resource {
 })
 
 func resourceGitlabProjectIssueBoardCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	options := gitlab.CreateIssueBoardOptions{
@@ -80,7 +80,7 @@ func resourceGitlabProjectIssueBoardCreate(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabProjectIssueBoardRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, issueBoardID, err := resourceGitlabProjectIssueBoardParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -88,12 +88,10 @@ func resourceGitlabProjectIssueBoardRead(ctx context.Context, d *schema.Resource
 
 	log.Printf("[DEBUG] read Project Issue Board in project %q with id %q", project, issueBoardID)
 	issueBoard, _, err := client.Boards.GetIssueBoard(project, issueBoardID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, issueBoard, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] Project Issue Board in project %s with id %d not found, removing from state", project, issueBoardID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -105,7 +103,7 @@ func resourceGitlabProjectIssueBoardRead(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabProjectIssueBoardUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, issueBoardID, err := resourceGitlabProjectIssueBoardParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -156,7 +154,7 @@ func resourceGitlabProjectIssueBoardUpdate(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabProjectIssueBoardDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, issueBoardID, err := resourceGitlabProjectIssueBoardParseID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
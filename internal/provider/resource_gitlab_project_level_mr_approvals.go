@@ -62,7 +62,7 @@ var _ = registerResource("gitlab_project_level_mr_approvals", func() *schema.Res
 })
 
 func resourceGitlabProjectLevelMRApprovalsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	projectId := d.Get("project_id").(int)
 
@@ -85,7 +85,7 @@ func resourceGitlabProjectLevelMRApprovalsCreate(ctx context.Context, d *schema.
 }
 
 func resourceGitlabProjectLevelMRApprovalsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	projectId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -95,12 +95,10 @@ func resourceGitlabProjectLevelMRApprovalsRead(ctx context.Context, d *schema.Re
 	log.Printf("[DEBUG] Reading gitlab approval configuration for project %q", projectId)
 
 	approvalConfig, _, err := client.Projects.GetApprovalConfiguration(projectId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, approvalConfig, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab project approval configuration not found for project %d", projectId)
-			d.SetId("")
-			return nil
-		}
 		return diag.Errorf("couldn't read approval configuration: %v", err)
 	}
 
@@ -115,7 +113,7 @@ func resourceGitlabProjectLevelMRApprovalsRead(ctx context.Context, d *schema.Re
 }
 
 func resourceGitlabProjectLevelMRApprovalsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	options := &gitlab.ChangeApprovalConfigurationOptions{}
 
 	projectId := d.Id()
@@ -145,7 +143,7 @@ func resourceGitlabProjectLevelMRApprovalsUpdate(ctx context.Context, d *schema.
 }
 
 func resourceGitlabProjectLevelMRApprovalsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectId := d.Id()
 
 	options := &gitlab.ChangeApprovalConfigurationOptions{
@@ -59,7 +59,7 @@ var _ = registerResource("gitlab_project_membership", func() *schema.Resource {
 })
 
 func resourceGitlabProjectMembershipCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	userId := d.Get("user_id").(int)
 	projectId := d.Get("project_id").(string)
@@ -83,7 +83,7 @@ func resourceGitlabProjectMembershipCreate(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabProjectMembershipRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	id := d.Id()
 	log.Printf("[DEBUG] read gitlab project projectMember %s", id)
 
@@ -93,12 +93,10 @@ func resourceGitlabProjectMembershipRead(ctx context.Context, d *schema.Resource
 	}
 
 	projectMember, _, err := client.ProjectMembers.GetProjectMember(projectId, userId, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, projectMember, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab project membership for %s not found so removing from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -119,7 +117,7 @@ func projectIdAndUserIdFromId(id string) (string, int, error) {
 }
 
 func resourceGitlabProjectMembershipUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	userId := d.Get("user_id").(int)
 	projectId := d.Get("project_id").(string)
@@ -140,7 +138,7 @@ func resourceGitlabProjectMembershipUpdate(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabProjectMembershipDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	id := d.Id()
 	projectId, userId, err := projectIdAndUserIdFromId(id)
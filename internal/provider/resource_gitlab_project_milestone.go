@@ -34,7 +34,7 @@ var _ = registerResource("gitlab_project_milestone", func() *schema.Resource {
 })
 
 func resourceGitlabProjectMilestoneCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	title := d.Get("title").(string)
 
@@ -82,7 +82,7 @@ func resourceGitlabProjectMilestoneCreate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceGitlabProjectMilestoneRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, milestoneID, err := resourceGitLabProjectMilestoneParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -90,12 +90,10 @@ func resourceGitlabProjectMilestoneRead(ctx context.Context, d *schema.ResourceD
 
 	log.Printf("[DEBUG] read gitlab milestone in project %s with ID %d", project, milestoneID)
 	milestone, resp, err := client.Milestones.GetMilestone(project, milestoneID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, milestone, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[WARN] recieved 404 for gitlab milestone ID %d in project %s, removing from state", milestoneID, project)
-			d.SetId("")
-			return nil
-		}
 		log.Printf("[WARN] failed to read gitlab milestone ID %d in project %s. Response %v", milestoneID, project, resp)
 		return diag.FromErr(err)
 	}
@@ -108,7 +106,7 @@ func resourceGitlabProjectMilestoneRead(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceGitlabProjectMilestoneUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, milestoneID, err := resourceGitLabProjectMilestoneParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -152,7 +150,7 @@ func resourceGitlabProjectMilestoneUpdate(ctx context.Context, d *schema.Resourc
 }
 
 func resourceGitlabProjectMilestoneDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, milestoneID, err := resourceGitLabProjectMilestoneParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
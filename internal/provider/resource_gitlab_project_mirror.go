@@ -92,7 +92,7 @@ import_url, mirror, and mirror_trigger_builds properties on the gitlab_project r
 })
 
 func resourceGitlabProjectMirrorCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	projectID := d.Get("project").(string)
 	URL := d.Get("url").(string)
@@ -121,7 +121,7 @@ func resourceGitlabProjectMirrorCreate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabProjectMirrorUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	mirrorID := d.Get("mirror_id").(int)
 	projectID := d.Get("project").(string)
@@ -144,7 +144,7 @@ func resourceGitlabProjectMirrorUpdate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabProjectMirrorDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	mirrorID := d.Get("mirror_id").(int)
 	projectID := d.Get("project").(string)
@@ -176,7 +176,7 @@ func resourceGitlabProjectMirrorDelete(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabProjectMirrorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	ids := strings.Split(d.Id(), ":")
 	projectID := ids[0]
@@ -192,8 +192,7 @@ func resourceGitlabProjectMirrorRead(ctx context.Context, d *schema.ResourceData
 	}
 
 	if mirror == nil {
-		log.Printf("[DEBUG] mirror %d in project %s not found, removing from state", mirrorID, projectID)
-		d.SetId("")
+		removeGoneFromState(ctx, d, nil, errGone)
 		return nil
 	}
 
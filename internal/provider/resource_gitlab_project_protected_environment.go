@@ -110,7 +110,7 @@ func resourceGitlabProjectProtectedEnvironmentCreate(ctx context.Context, d *sch
 
 	log.Printf("[DEBUG] Project %s create gitlab protected environment %q", project, *options.Name)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	protectedEnvironment, _, err := client.ProtectedEnvironments.ProtectRepositoryEnvironments(project, options, gitlab.WithContext(ctx))
 	if err != nil {
@@ -136,15 +136,13 @@ func resourceGitlabProjectProtectedEnvironmentRead(ctx context.Context, d *schem
 
 	log.Printf("[DEBUG] Project %s read gitlab protected environment %q", project, environment)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	protectedEnvironment, _, err := client.ProtectedEnvironments.GetProtectedEnvironment(project, environment, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, protectedEnvironment, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] Project %s gitlab protected environment %q not found, removing from state", project, environment)
-			d.SetId("")
-			return nil
-		}
 		return diag.Errorf("error getting gitlab project %q protected environment %q: %v", project, environment, err)
 	}
 	d.Set("required_approval_count", protectedEnvironment.RequiredApprovalCount)
@@ -164,7 +162,7 @@ func resourceGitlabProjectProtectedEnvironmentDelete(ctx context.Context, d *sch
 
 	log.Printf("[DEBUG] Project %s delete gitlab project-level protected environment %s", project, environmentName)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	_, err = client.ProtectedEnvironments.UnprotectEnvironment(project, environmentName, gitlab.WithContext(ctx))
 	if err != nil {
@@ -40,7 +40,7 @@ var _ = registerResource("gitlab_project_runner_enablement", func() *schema.Reso
 })
 
 func resourceGitlabProjectRunnerEnablementCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	projectID := d.Get("project").(string)
 	runnerID := d.Get("runner_id").(int)
 	options := &gitlab.EnableProjectRunnerOptions{
@@ -61,7 +61,7 @@ func resourceGitlabProjectRunnerEnablementCreate(ctx context.Context, d *schema.
 }
 
 func resourceGitlabProjectRunnerEnablementRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, runnerID, err := projectAndRunnerFromID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -71,11 +71,17 @@ func resourceGitlabProjectRunnerEnablementRead(ctx context.Context, d *schema.Re
 
 	// Get the project id from `project`, which can be either the numeric ID or a name
 	projectDetails, _, err := client.Projects.GetProject(project, &gitlab.GetProjectOptions{}, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, projectDetails, err) {
+		return nil
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	runnerdetails, _, err := client.Runners.GetRunnerDetails(runnerID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, runnerdetails, err) {
+		return nil
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -90,8 +96,7 @@ func resourceGitlabProjectRunnerEnablementRead(ctx context.Context, d *schema.Re
 	}
 
 	if !found {
-		log.Printf("[WARN] removing project runner: %v from state because it no longer exists in gitlab", runnerID)
-		d.SetId("")
+		removeGoneFromState(ctx, d, nil, errGone)
 		return nil
 	}
 
@@ -119,7 +124,7 @@ func projectAndRunnerFromID(id string) (string, int, error) {
 }
 
 func resourceGitlabProjectRunnerEnablementDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	projectID, runnerID, err := projectAndRunnerFromID(d.Id())
 	if err != nil {
@@ -68,7 +68,7 @@ var _ = registerResource("gitlab_project_share_group", func() *schema.Resource {
 })
 
 func resourceGitlabProjectShareGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	groupId := d.Get("group_id").(int)
 	projectId := d.Get("project_id").(string)
@@ -98,7 +98,7 @@ func resourceGitlabProjectShareGroupCreate(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabProjectShareGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	id := d.Id()
 	log.Printf("[DEBUG] read gitlab project projectMember %s", id)
 
@@ -108,12 +108,10 @@ func resourceGitlabProjectShareGroupRead(ctx context.Context, d *schema.Resource
 	}
 
 	projectInformation, _, err := client.Projects.GetProject(projectId, nil, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, projectInformation, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] failed to read gitlab project %s: %s", id, err)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -141,7 +139,7 @@ func projectIdAndGroupIdFromId(id string) (string, int, error) {
 }
 
 func resourceGitlabProjectShareGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	id := d.Id()
 	projectId, groupId, err := projectIdAndGroupIdFromId(id)
@@ -42,7 +42,7 @@ var _ = registerResource("gitlab_project_tag", func() *schema.Resource {
 })
 
 func resourceGitlabProjectTagCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	name := d.Get("name").(string)
 	project := d.Get("project").(string)
 	ref := d.Get("ref").(string)
@@ -63,7 +63,7 @@ func resourceGitlabProjectTagCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabProjectTagRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, name, err := parseTwoPartID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -71,12 +71,10 @@ func resourceGitlabProjectTagRead(ctx context.Context, d *schema.ResourceData, m
 
 	log.Printf("[DEBUG] read gitlab tag %s/%s", project, name)
 	tag, resp, err := client.Tags.GetTag(project, name, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, tag, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] recieved 404 for gitlab tag %s/%s, removing from state", project, name)
-			d.SetId("")
-			return diag.FromErr(err)
-		}
 		log.Printf("[DEBUG] failed to read gitlab tag %s/%s response %v", project, name, resp)
 		return diag.FromErr(err)
 	}
@@ -97,7 +95,7 @@ func resourceGitlabProjectTagRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceGitlabProjectTagDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, name, err := parseTwoPartID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -477,6 +477,158 @@ func TestAccGitlabProject_archiveOnDestroy(t *testing.T) {
 	})
 }
 
+func TestAccGitlabProject_fork(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckGitlabProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectConfigFork(rInt, "gitlab_project.upstream.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("gitlab_project.fork", "forked_from_project_id", "gitlab_project.upstream", "id"),
+					resource.TestCheckResourceAttr("gitlab_project.fork", "default_branch", "main"),
+					resource.TestCheckResourceAttr("gitlab_project.fork", "wiki_enabled", "false"),
+				),
+			},
+			// Remove the fork relationship
+			{
+				Config: testAccGitlabProjectConfigFork(rInt, "0"),
+				Check:  resource.TestCheckResourceAttr("gitlab_project.fork", "forked_from_project_id", "0"),
+			},
+			// Add the fork relationship again
+			{
+				Config: testAccGitlabProjectConfigFork(rInt, "gitlab_project.upstream.id"),
+				Check:  resource.TestCheckResourceAttrPair("gitlab_project.fork", "forked_from_project_id", "gitlab_project.upstream", "id"),
+			},
+			{
+				ResourceName:            "gitlab_project.fork",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"fork_branches", "mr_default_target_self"},
+			},
+		},
+	})
+}
+
+func TestAccGitlabProject_deletionProtection(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckGitlabProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectConfigDeletionProtection(rInt, true),
+			},
+			// Verify that the protected project can't be destroyed
+			{
+				Config:      testAccGitlabProjectConfigDeletionProtection(rInt, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`gitlab_project \d+ is protected from deletion`),
+			},
+			// Disable the protection, so that the project can be destroyed
+			{
+				Config: testAccGitlabProjectConfigDeletionProtection(rInt, false),
+			},
+		},
+	})
+}
+
+func TestAccGitlabProject_avatar(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckGitlabProjectDestroy,
+		Steps: []resource.TestStep{
+			// Create a project with an avatar
+			{
+				Config: testAccGitlabProjectConfigAvatar(rInt, "avatar.png"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_project.foo", "avatar_url"),
+					resource.TestCheckResourceAttr("gitlab_project.foo", "avatar_hash", "8d29d9c393facb9d86314eb347a03fde503f2c0422bf55af7df086deb126107e"),
+				),
+			},
+			{
+				ResourceName:            "gitlab_project.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"avatar", "avatar_hash"},
+			},
+			// Update the avatar
+			{
+				Config: testAccGitlabProjectConfigAvatar(rInt, "avatar-update.png"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_project.foo", "avatar_url"),
+					resource.TestCheckResourceAttr("gitlab_project.foo", "avatar_hash", "a58bd926fd3baabd41c56e810f62ade8705d18a4e280fb35764edb4b778444db"),
+				),
+			},
+			// Remove the avatar
+			{
+				SkipFunc: isGitLabVersionLessThan(context.Background(), testGitlabClient, "15.4"),
+				Config:   testAccGitlabProjectConfigAvatar(rInt, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_project.foo", "avatar_url", ""),
+					resource.TestCheckResourceAttr("gitlab_project.foo", "avatar_hash", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGitlabProject_delayedDeletion(t *testing.T) {
+	var project gitlab.Project
+	rInt := acctest.RandInt()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			// The project must be gone, instead of only being marked for deletion.
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != "gitlab_project" {
+					continue
+				}
+				if _, _, err := testGitlabClient.Projects.GetProject(rs.Primary.ID, nil); !is404(err) {
+					return fmt.Errorf("expected project %s to be removed permanently, got: %v", rs.Primary.ID, err)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				SkipFunc: isRunningInCE,
+				Config:   testAccGitlabProjectConfigPermanentlyRemoveOnDestroy(rInt, true),
+				Check:    testAccCheckGitlabProjectExists("gitlab_project.foo", &project),
+			},
+			// Mark the project for deletion outside of Terraform, so that it's restored
+			{
+				SkipFunc: isRunningInCE,
+				PreConfig: func() {
+					if _, err := testGitlabClient.Projects.DeleteProject(project.ID); err != nil {
+						t.Fatalf("failed to delete project: %v", err)
+					}
+				},
+				Config: testAccGitlabProjectConfigPermanentlyRemoveOnDestroy(rInt, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlab_project.foo", "marked_for_deletion_at", ""),
+					func(*terraform.State) error {
+						restored, _, err := testGitlabClient.Projects.GetProject(project.ID, nil)
+						if err != nil {
+							return err
+						}
+						if restored.MarkedForDeletionAt != nil {
+							return fmt.Errorf("expected project %d to be restored, it's still marked for deletion", project.ID)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 func TestAccGitlabProject_setSinglePushRuleToDefault(t *testing.T) {
 	rInt := acctest.RandInt()
 
@@ -2097,6 +2249,77 @@ resource "gitlab_project" "foo" {
 	`, rInt, rInt)
 }
 
+func testAccGitlabProjectConfigFork(rInt int, forkedFromProjectID string) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "upstream" {
+  name                   = "upstream-%[1]d"
+  initialize_with_readme = true
+  visibility_level       = "public"
+}
+
+resource "gitlab_project" "fork" {
+  name                   = "fork-%[1]d"
+  path                   = "fork-%[1]d"
+  forked_from_project_id = %[2]s
+  fork_branches          = "main"
+  mr_default_target_self = true
+  wiki_enabled           = false
+  visibility_level       = "public"
+}
+	`, rInt, forkedFromProjectID)
+}
+
+func testAccGitlabProjectConfigDeletionProtection(rInt int, deletionProtection bool) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%d"
+  path = "foo.%d"
+  description = "Terraform acceptance tests"
+  deletion_protection = %t
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+	`, rInt, rInt, deletionProtection)
+}
+
+func testAccGitlabProjectConfigAvatar(rInt int, avatar string) string {
+	var avatarConfig string
+	if avatar != "" {
+		avatarConfig = fmt.Sprintf(`
+  avatar      = "${path.module}/testdata/gitlab_topic/%[1]s"
+  avatar_hash = filesha256("${path.module}/testdata/gitlab_topic/%[1]s")`, avatar)
+	}
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%d"
+  path = "foo.%d"
+  description = "Terraform acceptance tests"
+  %s
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+	`, rInt, rInt, avatarConfig)
+}
+
+func testAccGitlabProjectConfigPermanentlyRemoveOnDestroy(rInt int, permanentlyRemove bool) string {
+	return fmt.Sprintf(`
+resource "gitlab_project" "foo" {
+  name = "foo-%d"
+  path = "foo.%d"
+  description = "Terraform acceptance tests"
+  permanently_remove_on_destroy = %t
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+	`, rInt, rInt, permanentlyRemove)
+}
+
 func testAccGitlabProjectConfigArchiveOnDestroy(rInt int) string {
 	return fmt.Sprintf(`
 resource "gitlab_project" "foo" {
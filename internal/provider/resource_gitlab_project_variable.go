@@ -31,7 +31,7 @@ var _ = registerResource("gitlab_project_variable", func() *schema.Resource {
 })
 
 func resourceGitlabProjectVariableCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	key := d.Get("key").(string)
@@ -65,7 +65,7 @@ func resourceGitlabProjectVariableCreate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabProjectVariableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	var (
 		project          string
@@ -92,12 +92,10 @@ func resourceGitlabProjectVariableRead(ctx context.Context, d *schema.ResourceDa
 	log.Printf("[DEBUG] read gitlab project variable %q", d.Id())
 
 	variable, _, err := client.ProjectVariables.GetVariable(project, key, nil, gitlab.WithContext(ctx), withEnvironmentScopeFilter(ctx, environmentScope))
+	if removeGoneFromState(ctx, d, variable, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] read gitlab project variable %q was not found, removing from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return augmentVariableClientError(d, err)
 	}
 
@@ -109,7 +107,7 @@ func resourceGitlabProjectVariableRead(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabProjectVariableUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 	key := d.Get("key").(string)
@@ -137,7 +135,7 @@ func resourceGitlabProjectVariableUpdate(ctx context.Context, d *schema.Resource
 }
 
 func resourceGitlabProjectVariableDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	key := d.Get("key").(string)
 	environmentScope := d.Get("environment_scope").(string)
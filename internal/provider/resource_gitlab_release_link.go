@@ -30,7 +30,7 @@ var _ = registerResource("gitlab_release_link", func() *schema.Resource {
 })
 
 func resourceGitlabReleaseLinkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	tagName := d.Get("tag_name").(string)
 	name := d.Get("name").(string)
@@ -60,7 +60,7 @@ func resourceGitlabReleaseLinkCreate(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabReleaseLinkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, tagName, linkID, err := resourceGitLabReleaseLinkParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -68,12 +68,10 @@ func resourceGitlabReleaseLinkRead(ctx context.Context, d *schema.ResourceData,
 
 	log.Printf("[DEBUG] read release link project/tagName/linkID: %s/%s/%d", project, tagName, linkID)
 	releaseLink, resp, err := client.ReleaseLinks.GetReleaseLink(project, tagName, linkID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, releaseLink, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[WARN] recieved 404 for release link project/tagName/linkID: %s/%s/%d. Removing from state", project, tagName, linkID)
-			d.SetId("")
-			return nil
-		}
 		log.Printf("[WARN] failed to read release link project/tagName/linkID: %s/%s/%d. Response %v", project, tagName, linkID, resp)
 		return diag.FromErr(err)
 	}
@@ -86,7 +84,7 @@ func resourceGitlabReleaseLinkRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabReleaseLinkUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, tagName, linkID, err := resourceGitLabReleaseLinkParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -118,7 +116,7 @@ func resourceGitlabReleaseLinkUpdate(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabReleaseLinkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, tagName, linkID, err := resourceGitLabReleaseLinkParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
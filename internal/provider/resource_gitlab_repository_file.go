@@ -18,19 +18,24 @@ import (
 
 const encoding = "base64"
 
-// NOTE: this lock is a bit of a hack to prevent parallel calls to the GitLab Repository Files API.
+// resourceGitlabRepositoryFileLockKey returns the key of the provider meta lock
+// used to serialize calls to the GitLab Repository Files API for the same branch.
+//
+// NOTE: if the API is called concurrently for the same branch, it will return a 400 error along the lines of:
 //
-//	If it is called concurrently, the API will return a 400 error along the lines of:
 //	```
 //	(400 Bad Request) DELETE https://gitlab.com/api/v4/projects/30716/repository/files/somefile.yaml: 400
 //	{message: 9:Could not update refs/heads/master. Please refresh and try again..}
 //	```
 //
+//	Calls for different branches or projects don't conflict and may run in parallel.
 //	This lock only solves half of the problem, where the provider is responsible for
 //	the concurrency. The other half is if the API is called outside of terraform at the same time
 //	this resource makes calls to the API.
 //	To mitigate this, simple retries are used.
-var resourceGitlabRepositoryFileApiLock = newLock()
+func resourceGitlabRepositoryFileLockKey(project, branch string) string {
+	return lockKey("project", project, "branch", branch)
+}
 
 var _ = registerResource("gitlab_repository_file", func() *schema.Resource {
 	return &schema.Resource{
@@ -100,14 +105,16 @@ func resourceGitlabRepositoryFileCreate(ctx context.Context, d *schema.ResourceD
 	project := d.Get("project").(string)
 	filePath := d.Get("file_path").(string)
 
+	locks := meta.(*providerMeta).locks
+	key := resourceGitlabRepositoryFileLockKey(project, d.Get("branch").(string))
 	log.Printf("[DEBUG] gitlab_repository_file: waiting for lock to create %s/%s", project, filePath)
-	if err := resourceGitlabRepositoryFileApiLock.lock(ctx); err != nil {
+	if err := locks.lock(ctx, key); err != nil {
 		return diag.FromErr(err)
 	}
-	defer resourceGitlabRepositoryFileApiLock.unlock()
+	defer locks.unlock(key)
 	log.Printf("[DEBUG] gitlab_repository_file: got lock to create %s/%s", project, filePath)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	// NOTE: for backwards-compatibility reasons, we also support an already given base64 encoding,
 	//       otherwise we encode the `content` to base64.
 	content := d.Get("content").(string)
@@ -151,7 +158,7 @@ func resourceGitlabRepositoryFileCreate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceGitlabRepositoryFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, branch, filePath, err := resourceGitLabRepositoryFileParseId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -162,12 +169,10 @@ func resourceGitlabRepositoryFileRead(ctx context.Context, d *schema.ResourceDat
 	}
 
 	repositoryFile, _, err := client.RepositoryFiles.GetFile(project, filePath, options, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, repositoryFile, err) {
+		return nil
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "404 File Not Found") {
-			log.Printf("[WARN] file %s not found, removing from state", filePath)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -199,14 +204,16 @@ func resourceGitlabRepositoryFileUpdate(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 
+	locks := meta.(*providerMeta).locks
+	key := resourceGitlabRepositoryFileLockKey(project, branch)
 	log.Printf("[DEBUG] gitlab_repository_file: waiting for lock to update %s/%s", project, filePath)
-	if err := resourceGitlabRepositoryFileApiLock.lock(ctx); err != nil {
+	if err := locks.lock(ctx, key); err != nil {
 		return diag.FromErr(err)
 	}
-	defer resourceGitlabRepositoryFileApiLock.unlock()
+	defer locks.unlock(key)
 	log.Printf("[DEBUG] gitlab_repository_file: got lock to update %s/%s", project, filePath)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	readOptions := &gitlab.GetFileOptions{
 		Ref: gitlab.String(branch),
@@ -266,14 +273,16 @@ func resourceGitlabRepositoryFileDelete(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 
+	locks := meta.(*providerMeta).locks
+	key := resourceGitlabRepositoryFileLockKey(project, branch)
 	log.Printf("[DEBUG] gitlab_repository_file: waiting for lock to delete %s/%s", project, filePath)
-	if err := resourceGitlabRepositoryFileApiLock.lock(ctx); err != nil {
+	if err := locks.lock(ctx, key); err != nil {
 		return diag.FromErr(err)
 	}
-	defer resourceGitlabRepositoryFileApiLock.unlock()
+	defer locks.unlock(key)
 	log.Printf("[DEBUG] gitlab_repository_file: got lock to delete %s/%s", project, filePath)
 
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	readOptions := &gitlab.GetFileOptions{
 		Ref: gitlab.String(branch),
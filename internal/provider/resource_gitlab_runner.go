@@ -100,7 +100,7 @@ The runner will be registered at a group level if the token used is from a group
 })
 
 func resourceGitLabRunnerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	options := &gitlab.RegisterNewRunnerOptions{
 		Token: gitlab.String(d.Get("registration_token").(string)),
@@ -157,13 +157,16 @@ func resourceGitLabRunnerCreate(ctx context.Context, d *schema.ResourceData, met
 }
 
 func resourceGitLabRunnerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	runnerID, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	runner, _, err := client.Runners.GetRunnerDetails(runnerID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, runner, err) {
+		return nil
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -185,7 +188,7 @@ func resourceGitLabRunnerRead(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGitLabRunnerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	runnerID := d.Id()
 
 	options := &gitlab.UpdateRunnerDetailsOptions{}
@@ -235,7 +238,7 @@ func resourceGitLabRunnerUpdate(ctx context.Context, d *schema.ResourceData, met
 }
 
 func resourceGitLabRunnerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	runnerID, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -69,7 +69,7 @@ var _ = registerResource("gitlab_service_external_wiki", func() *schema.Resource
 })
 
 func resourceGitlabServiceExternalWikiCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	d.SetId(project)
 
@@ -88,18 +88,16 @@ func resourceGitlabServiceExternalWikiCreate(ctx context.Context, d *schema.Reso
 }
 
 func resourceGitlabServiceExternalWikiRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Id()
 
 	log.Printf("[DEBUG] read gitlab external wiki service for project %s", project)
 
 	service, _, err := client.Services.GetExternalWikiService(project, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, service, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab external wiki service not found for project %s", project)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -117,7 +115,7 @@ func resourceGitlabServiceExternalWikiRead(ctx context.Context, d *schema.Resour
 }
 
 func resourceGitlabServiceExternalWikiDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Id()
 
 	log.Printf("[DEBUG] delete gitlab external wiki service for project %s", project)
@@ -88,7 +88,7 @@ func resourceGitlabServiceGithubSetToState(d *schema.ResourceData, service *gitl
 }
 
 func resourceGitlabServiceGithubCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	log.Printf("[DEBUG] create gitlab github service for project %s", project)
@@ -108,21 +108,16 @@ func resourceGitlabServiceGithubCreate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabServiceGithubRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	log.Printf("[DEBUG] read gitlab github service for project %s", project)
 
 	service, _, err := client.Services.GetGithubService(project, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, service, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab service github not found %s / %s / %s",
-				project,
-				service.Title,
-				service.Properties.RepositoryURL)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -136,7 +131,7 @@ func resourceGitlabServiceGithubUpdate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabServiceGithubDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 
 	log.Printf("[DEBUG] delete gitlab github service for project %s", project)
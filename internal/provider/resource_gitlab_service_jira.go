@@ -144,7 +144,7 @@ var _ = registerResource("gitlab_service_jira", func() *schema.Resource {
 })
 
 func resourceGitlabServiceJiraCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 
@@ -165,18 +165,16 @@ func resourceGitlabServiceJiraCreate(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabServiceJiraRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Id()
 
 	log.Printf("[DEBUG] Read Gitlab Jira service %s", project)
 
 	jiraService, _, err := client.Services.GetJiraService(project, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, jiraService, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab jira service not found %s, removing from state", project)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -215,7 +213,7 @@ func resourceGitlabServiceJiraUpdate(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabServiceJiraDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	project := d.Get("project").(string)
 
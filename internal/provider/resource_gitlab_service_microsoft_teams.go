@@ -111,7 +111,7 @@ var _ = registerResource("gitlab_service_microsoft_teams", func() *schema.Resour
 })
 
 func resourceGitlabServiceMicrosoftTeamsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	d.SetId(project)
 
@@ -140,18 +140,16 @@ func resourceGitlabServiceMicrosoftTeamsCreate(ctx context.Context, d *schema.Re
 }
 
 func resourceGitlabServiceMicrosoftTeamsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Id()
 
 	log.Printf("[DEBUG] Read Gitlab Microsoft Teams service for project %s", d.Id())
 
 	teamsService, _, err := client.Services.GetMicrosoftTeamsService(project, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, teamsService, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] Unable to find Gitlab Microsoft Teams service in project %s, removing from state", project)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -180,7 +178,7 @@ func resourceGitlabServiceMicrosoftTeamsUpdate(ctx context.Context, d *schema.Re
 }
 
 func resourceGitlabServiceMicrosoftTeamsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Id()
 
 	log.Printf("[DEBUG] Delete Gitlab Microsoft Teams service for project %s", d.Id())
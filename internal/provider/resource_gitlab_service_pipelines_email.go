@@ -62,7 +62,7 @@ func resourceGitlabServicePipelinesEmailSetToState(d *schema.ResourceData, servi
 }
 
 func resourceGitlabServicePipelinesEmailCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	d.SetId(project)
 	options := &gitlab.SetPipelinesEmailServiceOptions{
@@ -82,18 +82,16 @@ func resourceGitlabServicePipelinesEmailCreate(ctx context.Context, d *schema.Re
 }
 
 func resourceGitlabServicePipelinesEmailRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Id()
 
 	log.Printf("[DEBUG] read gitlab pipelines emails service for project %s", project)
 
 	service, _, err := client.Services.GetPipelinesEmailService(project, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, service, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab pipelines emails service not found for project %s", project)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -103,7 +101,7 @@ func resourceGitlabServicePipelinesEmailRead(ctx context.Context, d *schema.Reso
 }
 
 func resourceGitlabServicePipelinesEmailDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Id()
 
 	log.Printf("[DEBUG] delete gitlab pipelines email service for project %s", project)
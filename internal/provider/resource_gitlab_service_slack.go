@@ -195,7 +195,7 @@ var _ = registerResource("gitlab_service_slack", func() *schema.Resource {
 })
 
 func resourceGitlabServiceSlackCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	d.SetId(project)
 
@@ -251,7 +251,7 @@ func resourceGitlabServiceSlackCreate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabServiceSlackRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	id := d.Id()
 	project := d.Get("project").(string)
 	if id != project && project != "" {
@@ -264,12 +264,10 @@ func resourceGitlabServiceSlackRead(ctx context.Context, d *schema.ResourceData,
 	log.Printf("[DEBUG] read gitlab slack service for project %s", project)
 
 	service, _, err := client.Services.GetSlackService(project, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, service, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab slack service not found %s", project)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -317,7 +315,7 @@ func resourceGitlabServiceSlackUpdate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceGitlabServiceSlackDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Id()
 
 	log.Printf("[DEBUG] delete gitlab slack service for project %s", project)
@@ -49,7 +49,7 @@ var _ = registerResource("gitlab_tag_protection", func() *schema.Resource {
 })
 
 func resourceGitlabTagProtectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	tag := gitlab.String(d.Get("tag").(string))
 	createAccessLevel := tagProtectionAccessLevelID[d.Get("create_access_level").(string)]
@@ -81,7 +81,7 @@ func resourceGitlabTagProtectionCreate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceGitlabTagProtectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project, tag, err := projectAndTagFromID(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -90,12 +90,10 @@ func resourceGitlabTagProtectionRead(ctx context.Context, d *schema.ResourceData
 	log.Printf("[DEBUG] read gitlab tag protection for project %s, tag %s", project, tag)
 
 	pt, _, err := client.ProtectedTags.GetProtectedTag(project, tag, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, pt, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab tag protection not found %s/%s", project, tag)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -114,7 +112,7 @@ func resourceGitlabTagProtectionRead(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceGitlabTagProtectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	project := d.Get("project").(string)
 	tag := d.Get("tag").(string)
 
@@ -71,21 +71,12 @@ var _ = registerResource("gitlab_topic", func() *schema.Resource {
 				Computed:    true,
 			},
 		},
-		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
-			if _, ok := rd.GetOk("avatar"); ok {
-				if v, ok := rd.GetOk("avatar_hash"); !ok || v.(string) == "" {
-					if err := rd.SetNewComputed("avatar_hash"); err != nil {
-						return err
-					}
-				}
-			}
-			return nil
-		},
+		CustomizeDiff: customizeDiffAvatarHash,
 	}
 })
 
 func resourceGitlabTopicCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	if err := resourceGitlabTopicEnsureTitleSupport(ctx, client, d); err != nil {
 		return diag.FromErr(err)
 	}
@@ -122,7 +113,7 @@ func resourceGitlabTopicCreate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGitlabTopicRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	topicID, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -131,12 +122,10 @@ func resourceGitlabTopicRead(ctx context.Context, d *schema.ResourceData, meta i
 	log.Printf("[DEBUG] read gitlab topic %d", topicID)
 
 	topic, _, err := client.Topics.GetTopic(topicID, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, topic, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab group %s not found so removing from state", d.Id())
-			d.SetId("")
-			return nil
-		}
 		return diag.Errorf("Failed to read topic %d: %s", topicID, err)
 	}
 
@@ -149,7 +138,7 @@ func resourceGitlabTopicRead(ctx context.Context, d *schema.ResourceData, meta i
 }
 
 func resourceGitlabTopicUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	options := &gitlab.UpdateTopicOptions{}
 	if err := resourceGitlabTopicEnsureTitleSupport(ctx, client, d); err != nil {
 		return diag.FromErr(err)
@@ -199,7 +188,7 @@ func resourceGitlabTopicUpdate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGitlabTopicDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	topicID, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return diag.Errorf("Failed to convert topic id %s to int: %s", d.Id(), err)
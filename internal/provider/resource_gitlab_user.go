@@ -38,7 +38,7 @@ var _ = registerResource("gitlab_user", func() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: constructSchema(map[string]*schema.Schema{
 			"username": {
 				Description: "The username of the user.",
 				Type:        schema.TypeString,
@@ -116,10 +116,19 @@ var _ = registerResource("gitlab_user", func() *schema.Resource {
 				Optional:    true,
 				Computed:    true,
 			},
-		},
+		}, resourceGitlabUserAvatarSchema(), map[string]*schema.Schema{
+			"deletion_protection": deletionProtectionSchema("user"),
+		}),
+		CustomizeDiff: customizeDiffAvatarHash,
 	}
 })
 
+func resourceGitlabUserAvatarSchema() map[string]*schema.Schema {
+	s := avatarSchema("user")
+	s["avatar"].Description += " The GitLab API doesn't support removing the avatar of a user, thus the current avatar is kept with a warning if the attribute is removed."
+	return s
+}
+
 func resourceGitlabUserSetToState(d *schema.ResourceData, user *gitlab.User) {
 	d.Set("username", user.Username)
 	d.Set("name", user.Name)
@@ -131,10 +140,11 @@ func resourceGitlabUserSetToState(d *schema.ResourceData, user *gitlab.User) {
 	d.Set("note", user.Note)
 	d.Set("state", user.State)
 	d.Set("namespace_id", user.NamespaceID)
+	d.Set("avatar_url", user.AvatarURL)
 }
 
 func resourceGitlabUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	options := &gitlab.CreateUserOptions{
 		Email:            gitlab.String(d.Get("email").(string)),
 		Password:         gitlab.String(d.Get("password").(string)),
@@ -162,6 +172,12 @@ func resourceGitlabUserCreate(ctx context.Context, d *schema.ResourceData, meta
 
 	d.SetId(fmt.Sprintf("%d", user.ID))
 
+	if _, ok := d.GetOk("avatar"); ok {
+		if err := updateAvatar(ctx, client, d, fmt.Sprintf("users/%d", user.ID)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	if d.Get("state") == "blocked" {
 		err := client.Users.BlockUser(user.ID, gitlab.WithContext(ctx))
 
@@ -180,18 +196,16 @@ func resourceGitlabUserCreate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGitlabUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	log.Printf("[DEBUG] import -- read gitlab user %s", d.Id())
 
 	id, _ := strconv.Atoi(d.Id())
 
 	user, _, err := client.Users.GetUser(id, gitlab.GetUsersOptions{}, gitlab.WithContext(ctx))
+	if removeGoneFromState(ctx, d, user, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("[DEBUG] gitlab user not found %d", id)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -200,7 +214,7 @@ func resourceGitlabUserRead(ctx context.Context, d *schema.ResourceData, meta in
 }
 
 func resourceGitlabUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	options := &gitlab.ModifyUserOptions{}
 
@@ -237,13 +251,14 @@ func resourceGitlabUserUpdate(ctx context.Context, d *schema.ResourceData, meta
 		options.Note = gitlab.String(d.Get("note").(string))
 	}
 
-	log.Printf("[DEBUG] update gitlab user %s", d.Id())
-
 	id, _ := strconv.Atoi(d.Id())
 
-	_, _, err := client.Users.ModifyUser(id, options, gitlab.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
+	// The `deletion_protection` is only stored in the state, thus the user isn't modified if only it has been changed.
+	if *options != (gitlab.ModifyUserOptions{}) {
+		log.Printf("[DEBUG] update gitlab user %s", d.Id())
+		if _, _, err := client.Users.ModifyUser(id, options, gitlab.WithContext(ctx)); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	if d.HasChange("state") {
@@ -277,11 +292,32 @@ func resourceGitlabUserUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
-	return resourceGitlabUserRead(ctx, d, meta)
+	var diags diag.Diagnostics
+	if avatarChanged(d) {
+		if _, ok := d.GetOk("avatar"); ok {
+			if err := updateAvatar(ctx, client, d, fmt.Sprintf("users/%d", id)); err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			d.Set("avatar_hash", "")
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The avatar of user %s is kept", d.Get("username").(string)),
+				Detail:   "The GitLab API doesn't support removing the avatar of a user, thus the current avatar is kept, see `avatar_url`. The user can remove it in their profile settings.",
+			})
+		}
+	}
+
+	return append(diags, resourceGitlabUserRead(ctx, d, meta)...)
 }
 
 func resourceGitlabUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
+
+	if diags := checkDeletionProtection(d, "gitlab_user"); diags != nil {
+		return diags
+	}
+
 	log.Printf("[DEBUG] Delete gitlab user %s", d.Id())
 
 	id, _ := strconv.Atoi(d.Id())
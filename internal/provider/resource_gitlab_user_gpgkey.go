@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -61,7 +60,7 @@ var _ = registerResource("gitlab_user_gpgkey", func() *schema.Resource {
 })
 
 func resourceGitlabUserGPGKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	options := &gitlab.AddGPGKeyOptions{
 		Key: gitlab.String(strings.TrimSpace(d.Get("key").(string))),
@@ -72,7 +71,7 @@ func resourceGitlabUserGPGKeyCreate(ctx context.Context, d *schema.ResourceData,
 	var err error
 	userID, userIDOk := d.GetOk("user_id")
 	if userIDOk {
-		isAdmin, err = isCurrentUserAdmin(ctx, client)
+		isAdmin, err = meta.(*providerMeta).isCurrentUserAdmin(ctx)
 		if err != nil {
 			return diag.Errorf("failed to check if user is admin for configuring GPG keys for a user")
 		}
@@ -98,7 +97,7 @@ func resourceGitlabUserGPGKeyCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabUserGPGKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	userID, keyID, err := resourceGitlabUserGPGKeyParseID(d.Id())
 	if err != nil {
@@ -111,12 +110,10 @@ func resourceGitlabUserGPGKeyRead(ctx context.Context, d *schema.ResourceData, m
 	} else {
 		key, _, err = client.Users.GetGPGKey(keyID, gitlab.WithContext(ctx))
 	}
+	if removeGoneFromState(ctx, d, key, err) {
+		return nil
+	}
 	if err != nil {
-		if is404(err) {
-			log.Printf("Could not find GPG key %d for user %d, removing from state", keyID, userID)
-			d.SetId("")
-			return nil
-		}
 		return diag.FromErr(err)
 	}
 
@@ -130,7 +127,7 @@ func resourceGitlabUserGPGKeyRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceGitlabUserGPGKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	var isAdmin bool
 	_, keyID, err := resourceGitlabUserGPGKeyParseID(d.Id())
@@ -139,7 +136,7 @@ func resourceGitlabUserGPGKeyDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if userID, ok := d.GetOk("user_id"); ok {
-		isAdmin, err = isCurrentUserAdmin(ctx, client)
+		isAdmin, err = meta.(*providerMeta).isCurrentUserAdmin(ctx)
 		if err != nil {
 			return diag.Errorf("failed to check if user is admin for configuring GPG keys for a user")
 		}
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -83,7 +82,7 @@ var _ = registerResource("gitlab_user_sshkey", func() *schema.Resource {
 })
 
 func resourceGitlabUserSSHKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 	userID := d.Get("user_id").(int)
 
 	options := &gitlab.AddSSHKeyOptions{
@@ -112,7 +111,7 @@ func resourceGitlabUserSSHKeyCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceGitlabUserSSHKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	userID, keyID, err := resourceGitlabUserSSHKeyParseID(d.Id())
 	if err != nil {
@@ -127,6 +126,9 @@ func resourceGitlabUserSSHKeyRead(ctx context.Context, d *schema.ResourceData, m
 	var key *gitlab.SSHKey
 	for options.Page != 0 && key == nil {
 		keys, resp, err := client.Users.ListSSHKeysForUser(userID, options, gitlab.WithContext(ctx))
+		if removeGoneFromState(ctx, d, nil, err) {
+			return nil
+		}
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -142,8 +144,7 @@ func resourceGitlabUserSSHKeyRead(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	if key == nil {
-		log.Printf("Could not find sshkey %d for user %d", keyID, userID)
-		d.SetId("")
+		removeGoneFromState(ctx, d, nil, errGone)
 		return nil
 	}
 
@@ -161,7 +162,7 @@ func resourceGitlabUserSSHKeyRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceGitlabUserSSHKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*gitlab.Client)
+	client := meta.(*providerMeta).client
 
 	userID, keyID, err := resourceGitlabUserSSHKeyParseID(d.Id())
 	if err != nil {
@@ -358,6 +358,44 @@ func TestAccGitlabUser_password_reset(t *testing.T) {
 	})
 }
 
+func TestAccGitlabUser_avatar(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckGitlabUserDestroy,
+		Steps: []resource.TestStep{
+			// Create a user with an avatar
+			{
+				Config: testAccGitlabUserConfigAvatar(rInt, "avatar.png"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_user.foo", "avatar_url"),
+					resource.TestCheckResourceAttr("gitlab_user.foo", "avatar_hash", "8d29d9c393facb9d86314eb347a03fde503f2c0422bf55af7df086deb126107e"),
+				),
+			},
+			{
+				ResourceName:      "gitlab_user.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"password",
+					"skip_confirmation",
+					"avatar",
+					"avatar_hash",
+				},
+			},
+			// Update the avatar
+			{
+				Config: testAccGitlabUserConfigAvatar(rInt, "avatar-update.png"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gitlab_user.foo", "avatar_url"),
+					resource.TestCheckResourceAttr("gitlab_user.foo", "avatar_hash", "a58bd926fd3baabd41c56e810f62ade8705d18a4e280fb35764edb4b778444db"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckGitlabUserExists(n string, user *gitlab.User) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -573,3 +611,16 @@ resource "gitlab_user" "foo" {
 }
   `, rInt, rInt, rInt, rInt)
 }
+
+func testAccGitlabUserConfigAvatar(rInt int, avatar string) string {
+	return fmt.Sprintf(`
+resource "gitlab_user" "foo" {
+  name             = "foo %[1]d"
+  username         = "listest%[1]d"
+  password         = "test%[1]dtt"
+  email            = "listest%[1]d@ssss.com"
+  avatar           = "${path.module}/testdata/gitlab_topic/%[2]s"
+  avatar_hash      = filesha256("${path.module}/testdata/gitlab_topic/%[2]s")
+}
+  `, rInt, avatar)
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// newAuditLogTransport wraps the given transport to append every mutating request to the audit log file at the given path,
+// i.e. every request other than `GET`, `HEAD` and `OPTIONS` and GraphQL queries, like newReadOnlyTransport rejects them.
+// Every attempt of a request is logged as one JSON line, see auditLogEntry, with the sensitive fields of its JSON body redacted.
+// The given transport is returned as is if no path is given.
+func newAuditLogTransport(transport http.RoundTripper, path string) (http.RoundTripper, error) {
+	if path == "" {
+		return transport, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file: %w", err)
+	}
+	return &auditLogTransport{transport: transport, path: path, file: file, now: time.Now}, nil
+}
+
+type auditLogTransport struct {
+	transport http.RoundTripper
+	path      string
+	now       func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// auditLogEntry is a single mutating request, stored as one JSON line in the audit log file.
+type auditLogEntry struct {
+	Timestamp string `json:"timestamp"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	// Status is the status code of the response, or 0 if the request failed without a response.
+	Status int `json:"status"`
+	// Error is set if the request failed without a response.
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+	// Resource is the type of the resource which sent the request, e.g. `gitlab_project`.
+	// Terraform doesn't pass the name of the resource to the provider, thus the full address is not available.
+	Resource string `json:"resource,omitempty"`
+	// Body is the JSON body of the request with its sensitive fields redacted. Other bodies, e.g. file uploads, are omitted.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+func (t *auditLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.transport.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+
+		// A RoundTripper must not modify the given request, thus the consumed body is set on a copy.
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/api/graphql") && isGraphQLQuery(body) {
+		return t.transport.RoundTrip(req)
+	}
+
+	start := t.now()
+	resp, err := t.transport.RoundTrip(req)
+
+	entry := auditLogEntry{
+		Timestamp:  start.UTC().Format(time.RFC3339Nano),
+		Method:     req.Method,
+		Path:       req.URL.EscapedPath(),
+		DurationMS: t.now().Sub(start).Milliseconds(),
+		Resource:   resourceTypeFromContext(req.Context()),
+	}
+	if redacted, ok := redactJSONBody(body, isAuditLogSensitiveKey); ok {
+		entry.Body = redacted
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+	}
+
+	if logErr := t.write(&entry); logErr != nil {
+		// A mutation which can't be audited must not go unnoticed.
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, logErr
+	}
+	return resp, err
+}
+
+func (t *auditLogTransport) write(entry *auditLogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, err := t.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write to audit log file %s: %w", t.path, err)
+	}
+	return nil
+}
+
+// isAuditLogSensitiveKey checks if the given JSON field contains a credential or a value which may be one,
+// e.g. the `value` of a CI/CD variable. The same fields are redacted from cassettes.
+func isAuditLogSensitiveKey(key string) bool {
+	return isCassetteSensitiveKey(key)
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitlab_auditLogTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	transport, err := newAuditLogTransport(http.DefaultTransport, path)
+	if err != nil {
+		t.Fatalf("failed to create transport: %v", err)
+	}
+	now := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+	transport.(*auditLogTransport).now = func() time.Time {
+		now = now.Add(250 * time.Millisecond)
+		return now
+	}
+	client := &http.Client{Transport: transport}
+
+	requests := []struct {
+		Method string
+		Path   string
+		Body   string
+	}{
+		{Method: http.MethodGet, Path: "/api/v4/projects/1"},
+		{Method: http.MethodPost, Path: "/api/v4/projects/1/variables", Body: `{"key":"FOO","value":"bar","masked":true}`},
+		{Method: http.MethodPut, Path: "/api/v4/projects/1/hooks/2", Body: `{"url":"https://example.com","token":"secret","push_events":false}`},
+		{Method: http.MethodPost, Path: "/api/v4/projects/1/uploads", Body: "--boundary\r\n"},
+		{Method: http.MethodDelete, Path: "/api/v4/projects/1"},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "query { currentUser { name } }"}`},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "mutation { createNote(input: {}) { errors } }"}`},
+	}
+	for _, r := range requests {
+		ctx := context.WithValue(context.Background(), resourceTypeContextKey{}, "gitlab_project")
+		req, _ := http.NewRequestWithContext(ctx, r.Method, server.URL+r.Path, strings.NewReader(r.Body))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: unexpected error: %v", r.Method, r.Path, err)
+		}
+		resp.Body.Close()
+	}
+
+	// A request without a response is logged with its error.
+	req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1:0/api/v4/projects", nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatalf("expected the request to fail")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	want := []string{
+		`{"timestamp":"2022-08-01T12:00:00.25Z","method":"POST","path":"/api/v4/projects/1/variables","status":201,"duration_ms":250,"resource":"gitlab_project","body":{"key":"FOO","masked":true,"value":"REDACTED"}}`,
+		`{"timestamp":"2022-08-01T12:00:00.75Z","method":"PUT","path":"/api/v4/projects/1/hooks/2","status":201,"duration_ms":250,"resource":"gitlab_project","body":{"push_events":false,"token":"REDACTED","url":"https://example.com"}}`,
+		`{"timestamp":"2022-08-01T12:00:01.25Z","method":"POST","path":"/api/v4/projects/1/uploads","status":201,"duration_ms":250,"resource":"gitlab_project"}`,
+		`{"timestamp":"2022-08-01T12:00:01.75Z","method":"DELETE","path":"/api/v4/projects/1","status":204,"duration_ms":250,"resource":"gitlab_project"}`,
+		`{"timestamp":"2022-08-01T12:00:02.25Z","method":"POST","path":"/api/graphql","status":201,"duration_ms":250,"resource":"gitlab_project","body":{"query":"mutation { createNote(input: {}) { errors } }"}}`,
+	}
+	if len(lines) != len(want)+1 {
+		t.Fatalf("got %d audit log entries expected %d:\n%s", len(lines), len(want)+1, content)
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Fatalf("got audit log entry %d:\n%s\nexpected:\n%s", i, lines[i], line)
+		}
+	}
+
+	var failed auditLogEntry
+	if err := json.Unmarshal([]byte(lines[len(want)]), &failed); err != nil || failed.Status != 0 || failed.Error == "" || failed.Resource != "" {
+		t.Fatalf("got audit log entry %s for a failed request, expected an error without status and resource", lines[len(want)])
+	}
+}
@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// newCacheTransport wraps the given transport with an in-memory cache for successful `GET` responses.
+//
+// Responses are cached per URL and credentials for the given ttl. A mutating request invalidates all
+// cached responses of the object it modifies, e.g. a `PUT /projects/42/variables/FOO` request
+// invalidates the cached responses for `/projects/42` and everything below it, as well as for `/projects`.
+// Changes which affect other objects, e.g. a group transfer changing the paths of its projects,
+// are not detected, thus the ttl is the upper bound for how stale a response may be.
+//
+// Objects are identified by the exact path of the request, i.e. the paths aren't normalized.
+// Thus, a mutating request for `/projects/42` doesn't invalidate the cached responses for the same project
+// requested as `/projects/group%2Fproject`, and vice versa.
+//
+// The credentials are taken from the request headers, thus the transport must be wrapped by the
+// transport which authenticates the requests, see newTokenTransport.
+func newCacheTransport(transport http.RoundTripper, ttl time.Duration) http.RoundTripper {
+	if ttl <= 0 {
+		return transport
+	}
+	return &cacheTransport{transport: transport, ttl: ttl, entries: make(map[string]*cacheEntry)}
+}
+
+type cacheTransport struct {
+	transport http.RoundTripper
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry
+	lastSweep time.Time
+}
+
+type cacheEntry struct {
+	path      string
+	expiresAt time.Time
+	status    int
+	header    http.Header
+	body      []byte
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		// GraphQL requests are sent as POST, but only mutations modify data.
+		if !strings.HasSuffix(req.URL.Path, "/api/graphql") {
+			t.invalidate(req.URL.EscapedPath())
+			defer t.invalidate(req.URL.EscapedPath())
+		}
+		return t.transport.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	if resp := t.get(key, req); resp != nil {
+		log.Printf("[DEBUG] GitLab API request %s %s served from cache", req.Method, req.URL.Path)
+		return resp, nil
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.put(key, &cacheEntry{
+		path:      req.URL.EscapedPath(),
+		expiresAt: time.Now().Add(t.ttl),
+		status:    resp.StatusCode,
+		header:    resp.Header.Clone(),
+		body:      body,
+	})
+	return resp, nil
+}
+
+// cacheKey builds the key of a request from its URL and credentials, so that responses
+// are never shared between different tokens.
+func cacheKey(req *http.Request) string {
+	credentials := sha256.Sum256([]byte(req.Header.Get("Authorization") + "\n" + req.Header.Get("Private-Token") + "\n" + req.Header.Get("Job-Token")))
+	return hex.EncodeToString(credentials[:]) + " " + req.URL.String()
+}
+
+func (t *cacheTransport) get(key string, req *http.Request) *http.Response {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(t.entries, key)
+		return nil
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(entry.status),
+		StatusCode:    entry.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(entry.body)),
+		ContentLength: int64(len(entry.body)),
+		Request:       req,
+	}
+}
+
+func (t *cacheTransport) put(key string, entry *cacheEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(t.lastSweep) > t.ttl {
+		for k, e := range t.entries {
+			if now.After(e.expiresAt) {
+				delete(t.entries, k)
+			}
+		}
+		t.lastSweep = now
+	}
+
+	t.entries[key] = entry
+}
+
+// invalidate removes all cached responses of the object the given request path belongs to.
+func (t *cacheTransport) invalidate(path string) {
+	object, collection := cacheObjectPath(path)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k, e := range t.entries {
+		if e.path == collection || e.path == object || strings.HasPrefix(e.path, object+"/") {
+			delete(t.entries, k)
+		}
+	}
+}
+
+// cacheObjectPath returns the path of the top-level object and of its collection for the given request path,
+// e.g. `/api/v4/projects/42/variables/FOO` -> `/api/v4/projects/42`, `/api/v4/projects`.
+func cacheObjectPath(path string) (string, string) {
+	prefix := ""
+	if i := strings.Index(path, "/api/v4/"); i >= 0 {
+		prefix, path = path[:i+len("/api/v4/")], path[i+len("/api/v4/"):]
+	}
+
+	parts := strings.SplitN(strings.Trim(path, "/"), "/", 3)
+	if len(parts) == 1 {
+		return prefix + parts[0], prefix + parts[0]
+	}
+	return prefix + parts[0] + "/" + parts[1], prefix + parts[0]
+}
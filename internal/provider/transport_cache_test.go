@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGitlab_cacheTransport(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.RequestURI()]++
+		if r.URL.Path == "/api/v4/projects/404" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(r.URL.RequestURI()))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newCacheTransport(http.DefaultTransport, time.Minute)}
+	do := func(method, path, token string) {
+		req, _ := http.NewRequest(method, server.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: unexpected error: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusOK && string(body) != path {
+			t.Fatalf("%s %s: got body %q", method, path, body)
+		}
+	}
+
+	cases := []struct {
+		Method       string
+		Path         string
+		Token        string
+		WantRequests int
+	}{
+		{Method: http.MethodGet, Path: "/api/v4/projects/1", Token: "a", WantRequests: 1},
+		{Method: http.MethodGet, Path: "/api/v4/projects/1", Token: "a", WantRequests: 1},
+		{Method: http.MethodGet, Path: "/api/v4/projects/1", Token: "b", WantRequests: 2},
+		{Method: http.MethodGet, Path: "/api/v4/projects/1/variables?page=2", Token: "a", WantRequests: 1},
+		{Method: http.MethodGet, Path: "/api/v4/projects/2", Token: "a", WantRequests: 1},
+		{Method: http.MethodGet, Path: "/api/v4/projects/404", Token: "a", WantRequests: 1},
+		{Method: http.MethodGet, Path: "/api/v4/projects/404", Token: "a", WantRequests: 2},
+		{Method: http.MethodPut, Path: "/api/v4/projects/1/variables/FOO", Token: "a", WantRequests: 1},
+		{Method: http.MethodGet, Path: "/api/v4/projects/1", Token: "a", WantRequests: 3},
+		{Method: http.MethodGet, Path: "/api/v4/projects/1/variables?page=2", Token: "a", WantRequests: 2},
+		{Method: http.MethodGet, Path: "/api/v4/projects/2", Token: "a", WantRequests: 1},
+	}
+
+	for _, tc := range cases {
+		do(tc.Method, tc.Path, tc.Token)
+		// The server counts the requests of all tokens together.
+		key := tc.Method + " " + tc.Path
+		if requests[key] != tc.WantRequests {
+			t.Fatalf("%s %s with token %q: got %d requests expected %d", tc.Method, tc.Path, tc.Token, requests[key], tc.WantRequests)
+		}
+	}
+}
+
+func TestGitlab_cacheObjectPath(t *testing.T) {
+	cases := []struct {
+		Path           string
+		WantObject     string
+		WantCollection string
+	}{
+		{Path: "/api/v4/projects/42/variables/FOO", WantObject: "/api/v4/projects/42", WantCollection: "/api/v4/projects"},
+		{Path: "/api/v4/projects", WantObject: "/api/v4/projects", WantCollection: "/api/v4/projects"},
+		{Path: "/gitlab/api/v4/groups/foo%2Fbar", WantObject: "/gitlab/api/v4/groups/foo%2Fbar", WantCollection: "/gitlab/api/v4/groups"},
+	}
+
+	for _, tc := range cases {
+		object, collection := cacheObjectPath(tc.Path)
+		if object != tc.WantObject || collection != tc.WantCollection {
+			t.Fatalf("%s: got %q, %q expected %q, %q", tc.Path, object, collection, tc.WantObject, tc.WantCollection)
+		}
+	}
+}
+
+func TestGitlab_cacheTransport_tokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fmt.Sprintf(`{"id": 1, "description": %q}`, r.Header.Get("Authorization"))))
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	config := Config{TokenFile: tokenFile, BaseURL: server.URL, RequestCacheTTL: time.Minute}
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// The responses are cached per resolved token, thus a rotated token doesn't get the responses of the previous one.
+	for _, token := range []string{"first-token", "rotated-token"} {
+		if err := os.WriteFile(tokenFile, []byte(token), 0600); err != nil {
+			t.Fatalf("failed to write token file: %v", err)
+		}
+		project, _, err := client.Projects.GetProject(1, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if project.Description != "Bearer "+token {
+			t.Fatalf("got response for %q expected the one for %q", project.Description, "Bearer "+token)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// cassetteModeRecord sends the requests to GitLab and appends the sanitized interactions to the cassette file.
+	cassetteModeRecord = "record"
+	// cassetteModeReplay serves the requests from the cassette file without sending them to GitLab.
+	cassetteModeReplay = "replay"
+)
+
+// cassetteRedacted replaces credentials in recorded interactions.
+const cassetteRedacted = "REDACTED"
+
+// cassetteSensitiveHeaders are never written to a cassette file.
+var cassetteSensitiveHeaders = []string{"Authorization", "Private-Token", "Job-Token", "Cookie", "Set-Cookie"}
+
+// newCassetteTransport wraps the given transport to record or replay the interactions with GitLab.
+//
+// In record mode every request is sent with the given transport and the interaction is appended
+// to the cassette file, with the credentials in the headers, the query strings and the JSON and form bodies redacted.
+// In replay mode no request is sent at all. A request is answered with the next recorded response
+// for the same method and URL, independent of the host of the base URL. If a request is repeated more often
+// than it was recorded, e.g. when polling for a state, the last recorded response is served again.
+//
+// All transports of the same cassette file share its interactions, so that the clients of the
+// acceptance tests and of the provider instances record into and replay from the same file.
+func newCassetteTransport(transport http.RoundTripper, mode string, path string) (http.RoundTripper, error) {
+	if path == "" {
+		return nil, fmt.Errorf("a cassette file is required for the cassette mode %q", mode)
+	}
+
+	c, err := openCassette(mode, path)
+	if err != nil {
+		return nil, err
+	}
+	return &cassetteTransport{transport: transport, cassette: c}, nil
+}
+
+type cassetteTransport struct {
+	transport http.RoundTripper
+	cassette  *cassette
+}
+
+// cassetteInteraction is a single request and its response, stored as one JSON line in the cassette file.
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type cassette struct {
+	mode string
+	path string
+
+	mu           sync.Mutex
+	file         *os.File
+	interactions map[string][]*cassetteInteraction
+	last         map[string]*cassetteInteraction
+}
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = make(map[string]*cassette)
+)
+
+// openCassette returns the cassette for the given file, which is truncated when it's first opened for recording.
+func openCassette(mode string, path string) (*cassette, error) {
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+
+	if c, ok := cassettes[path]; ok {
+		if c.mode != mode {
+			return nil, fmt.Errorf("the cassette file %s is already used in %s mode", path, c.mode)
+		}
+		return c, nil
+	}
+
+	c := &cassette{mode: mode, path: path}
+	switch mode {
+	case cassetteModeRecord:
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cassette file: %w", err)
+		}
+		c.file = file
+	case cassetteModeReplay:
+		interactions, err := readCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		c.interactions = interactions
+		c.last = make(map[string]*cassetteInteraction)
+	default:
+		return nil, fmt.Errorf("invalid cassette mode %q, must be one of %q or %q", mode, cassetteModeRecord, cassetteModeReplay)
+	}
+
+	cassettes[path] = c
+	return c, nil
+}
+
+// readCassette reads the interactions of the given cassette file grouped by their request key, in recorded order.
+func readCassette(path string) (map[string][]*cassetteInteraction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette file: %w", err)
+	}
+	defer file.Close()
+
+	interactions := make(map[string][]*cassetteInteraction)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var interaction cassetteInteraction
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("invalid interaction in line %d of cassette file %s: %w", line, path, err)
+		}
+		key := cassetteKey(interaction.Request.Method, interaction.Request.URL, []byte(interaction.Request.Body))
+		interactions[key] = append(interactions[key], &interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cassette file %s: %w", path, err)
+	}
+	return interactions, nil
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+
+		// A RoundTripper must not modify the given request, thus the consumed body is set on a copy.
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	url := cassetteURL(req)
+
+	if t.cassette.mode == cassetteModeReplay {
+		interaction := t.cassette.next(cassetteKey(req.Method, url, cassetteScrubBody(req.Header, body)))
+		if interaction == nil {
+			return nil, fmt.Errorf("no interaction recorded in cassette file %s for %s %s", t.cassette.path, req.Method, url)
+		}
+
+		log.Printf("[DEBUG] GitLab API request %s %s replayed from cassette", req.Method, url)
+		return &http.Response{
+			Status:        http.StatusText(interaction.Response.StatusCode),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &cassetteInteraction{
+		Request: cassetteRequest{
+			Method: req.Method,
+			URL:    url,
+			Header: cassetteScrubHeader(req.Header),
+			Body:   string(cassetteScrubBody(req.Header, body)),
+		},
+		Response: cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     cassetteScrubHeader(resp.Header),
+			Body:       string(cassetteScrubBody(resp.Header, respBody)),
+		},
+	}
+	if err := t.cassette.record(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// next returns the next recorded interaction for the given key, or the last one if all of them have been replayed.
+func (c *cassette) next(key string) *cassetteInteraction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if interactions := c.interactions[key]; len(interactions) > 0 {
+		c.interactions[key] = interactions[1:]
+		c.last[key] = interactions[0]
+	}
+	return c.last[key]
+}
+
+func (c *cassette) record(interaction *cassetteInteraction) error {
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write to cassette file %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// cassetteURL returns the URL of the request without scheme and host, so that a cassette can be replayed
+// with any base URL. The query parameters are sorted, because their order is not significant,
+// and the values of credential parameters, e.g. `private_token`, are redacted.
+func cassetteURL(req *http.Request) string {
+	url := req.URL.EscapedPath()
+	if query := req.URL.Query(); len(query) > 0 {
+		redactFormValues(query, isCassetteSensitiveKey)
+		url += "?" + query.Encode()
+	}
+	return url
+}
+
+// cassetteKey returns the key a request is matched with. Only GraphQL requests are matched by their body,
+// because they are all sent to the same URL. Other bodies may contain values like dates, which differ between runs.
+func cassetteKey(method string, url string, body []byte) string {
+	key := method + " " + url
+	if strings.HasSuffix(strings.SplitN(url, "?", 2)[0], "/api/graphql") {
+		key += " " + string(body)
+	}
+	return key
+}
+
+func cassetteScrubHeader(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, name := range cassetteSensitiveHeaders {
+		scrubbed.Del(name)
+	}
+	return scrubbed
+}
+
+// cassetteScrubBody redacts the values of all credential fields of a JSON or form body, e.g. `token`, `runners_token`
+// or `password`, like the `client_secret` and `refresh_token` of an OAuth2 token request.
+// The content type is taken from the given header of the request or response. Other bodies are returned as is.
+func cassetteScrubBody(header http.Header, body []byte) []byte {
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(string(body))
+		if err != nil || !redactFormValues(form, isCassetteSensitiveKey) {
+			return body
+		}
+		return []byte(form.Encode())
+	}
+
+	scrubbed, _ := redactJSONBody(body, isCassetteSensitiveKey)
+	return scrubbed
+}
+
+// redactFormValues replaces the non-empty values of all parameters of a query string or form body for which
+// isSensitive returns true with cassetteRedacted, and reports if any has been redacted.
+func redactFormValues(values url.Values, isSensitive func(key string) bool) bool {
+	redacted := false
+	for key, vs := range values {
+		if !isSensitive(key) {
+			continue
+		}
+		for i, v := range vs {
+			if v != "" {
+				vs[i] = cassetteRedacted
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+// redactJSONBody replaces the non-empty string values of all fields of a JSON body for which isSensitive returns true
+// with cassetteRedacted. It returns the body as is, and false, if it isn't JSON.
+func redactJSONBody(body []byte, isSensitive func(key string) bool) ([]byte, bool) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if len(body) == 0 || decoder.Decode(&value) != nil {
+		return body, false
+	}
+	if !redactJSONValue(value, isSensitive) {
+		return body, true
+	}
+
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return body, false
+	}
+	return redacted, true
+}
+
+// redactJSONValue redacts the sensitive fields in the given decoded JSON value and reports if any has been redacted.
+func redactJSONValue(value interface{}, isSensitive func(key string) bool) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if s, ok := field.(string); ok && s != "" && isSensitive(key) {
+				v[key] = cassetteRedacted
+				redacted = true
+				continue
+			}
+			redacted = redactJSONValue(field, isSensitive) || redacted
+		}
+	case []interface{}:
+		for _, item := range v {
+			redacted = redactJSONValue(item, isSensitive) || redacted
+		}
+	}
+	return redacted
+}
+
+// isCassetteSensitiveKey checks if the given field or parameter contains a credential, e.g. `private_token` or `password`,
+// or a value which may be one, e.g. the `value` of a CI/CD variable.
+func isCassetteSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	return key == "value" || strings.Contains(key, "token") || strings.Contains(key, "password") || strings.Contains(key, "secret")
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitlab_cassetteTransport(t *testing.T) {
+	f := newFakeGitlab(t)
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	t.Cleanup(func() {
+		cassettesMu.Lock()
+		delete(cassettes, path)
+		cassettesMu.Unlock()
+	})
+
+	// useCassette configures the clients created by Config.Client to use the cassette in the given mode.
+	useCassette := func(mode string) {
+		wrapBaseTransport = func(transport http.RoundTripper) (http.RoundTripper, error) {
+			return newCassetteTransport(transport, mode, path)
+		}
+	}
+	t.Cleanup(func() { wrapBaseTransport = nil })
+
+	interact := func(config Config) (string, error) {
+		client, err := config.Client(context.Background())
+		if err != nil {
+			return "", err
+		}
+		project, _, err := client.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: gitlab.String("foo")})
+		if err != nil {
+			return "", err
+		}
+		if _, _, err := client.Projects.AddProjectHook(project.ID, &gitlab.AddProjectHookOptions{URL: gitlab.String("https://example.com"), Token: gitlab.String("hook-secret")}); err != nil {
+			return "", err
+		}
+		// Polling the same URL more often than recorded replays the last response.
+		var paths []string
+		for i := 0; i < 2; i++ {
+			p, _, err := client.Projects.GetProject("root/foo", nil)
+			if err != nil {
+				return "", err
+			}
+			paths = append(paths, p.PathWithNamespace)
+		}
+		return strings.Join(paths, ","), nil
+	}
+
+	useCassette(cassetteModeRecord)
+	recorded, err := interact(Config{Token: fakeGitlabToken, BaseURL: f.BaseURL(), EarlyAuthFail: true})
+	if err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read cassette: %v", err)
+	}
+	for _, secret := range []string{fakeGitlabToken, "hook-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("the cassette contains the secret %q:\n%s", secret, data)
+		}
+	}
+
+	// The replay is matched without the host and doesn't send any request.
+	delete(cassettes, path)
+	useCassette(cassetteModeReplay)
+	replayConfig := Config{Token: "other-token", BaseURL: "http://127.0.0.1:1/api/v4/", EarlyAuthFail: true}
+	replayed, err := interact(replayConfig)
+	if err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if replayed != recorded || replayed != "root/foo,root/foo" {
+		t.Fatalf("got replayed %q expected %q", replayed, recorded)
+	}
+
+	client, err := replayConfig.Client(context.Background())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, _, err := client.Projects.GetProject("root/bar", nil); err == nil || !strings.Contains(err.Error(), "no interaction recorded in cassette file") {
+		t.Fatalf("expected an error for a request which wasn't recorded, got %v", err)
+	}
+
+	useCassette(cassetteModeRecord)
+	if _, err := (&Config{Token: "token"}).Client(context.Background()); err == nil {
+		t.Fatalf("expected an error for a cassette file used in a different mode")
+	}
+}
+
+func TestGitlab_cassetteScrubBody(t *testing.T) {
+	cases := map[string]string{
+		`{"token":"abc","name":"foo"}`:                      `{"name":"foo","token":"REDACTED"}`,
+		`[{"runners_token":"abc","id":1}]`:                  `[{"id":1,"runners_token":"REDACTED"}]`,
+		`{"user":{"password":"abc","reset_password":true}}`: `{"user":{"password":"REDACTED","reset_password":true}}`,
+		`{"token":"","masked":true}`:                        `{"token":"","masked":true}`,
+		`{"id":12345678901234567890,"value":"abc"}`:         `{"id":12345678901234567890,"value":"REDACTED"}`,
+		`not json`: `not json`,
+		`{"secret_token":"abc","application":{"secret":"abc"}}`:      `{"application":{"secret":"REDACTED"},"secret_token":"REDACTED"}`,
+		`{"note":"nothing to redact, thus the body is kept as is" }`: `{"note":"nothing to redact, thus the body is kept as is" }`,
+	}
+
+	for body, expected := range cases {
+		if got := string(cassetteScrubBody(http.Header{"Content-Type": {"application/json"}}, []byte(body))); got != expected {
+			t.Fatalf("%s: got %s expected %s", body, got, expected)
+		}
+	}
+
+	// The OAuth2 token requests are sent as form.
+	form := http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}
+	body := "client_id=app&client_secret=abc&grant_type=refresh_token&refresh_token=def"
+	expected := "client_id=app&client_secret=REDACTED&grant_type=refresh_token&refresh_token=REDACTED"
+	if got := string(cassetteScrubBody(form, []byte(body))); got != expected {
+		t.Fatalf("%s: got %s expected %s", body, got, expected)
+	}
+}
+
+func TestGitlab_cassetteURL(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://gitlab.example.com/api/v4/projects?private_token=abc&search=foo&access_token=", nil)
+	expected := "/api/v4/projects?access_token=&private_token=REDACTED&search=foo"
+	if got := cassetteURL(req); got != expected {
+		t.Fatalf("got %s expected %s", got, expected)
+	}
+}
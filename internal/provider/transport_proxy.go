@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc returns the function used by an http.Transport to select the proxy for a request.
+// Requests to hosts matching noProxy, a comma-separated list of hosts, domains and CIDRs, are sent directly.
+// If proxyURL is empty, the proxy is selected using the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY`
+// environment variables.
+func proxyFunc(proxyURL, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	if _, err := url.Parse(proxyURL); err != nil {
+		return nil, fmt.Errorf("failed to parse proxy url: %w", err)
+	}
+
+	proxy := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    noProxy,
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// newHeaderTransport wraps the given transport so that the given headers are added to every request.
+// Headers which are already set on a request, e.g. the `Authorization` header, are not overridden.
+func newHeaderTransport(transport http.RoundTripper, headers map[string]string) http.RoundTripper {
+	if len(headers) == 0 {
+		return transport
+	}
+	return &headerTransport{transport: transport, headers: headers}
+}
+
+type headerTransport struct {
+	transport http.RoundTripper
+	headers   map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the given request.
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+	return t.transport.RoundTrip(req)
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitlab_proxyFunc(t *testing.T) {
+	proxy, err := proxyFunc("http://proxy.example.com:3128", "gitlab.internal,.example.org")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		URL   string
+		Proxy string
+	}{
+		{
+			URL:   "https://gitlab.example.com/api/v4/projects",
+			Proxy: "http://proxy.example.com:3128",
+		},
+		{
+			URL:   "https://gitlab.internal/api/v4/projects",
+			Proxy: "",
+		},
+		{
+			URL:   "https://gitlab.example.org/api/v4/projects",
+			Proxy: "",
+		},
+	}
+
+	for _, tc := range cases {
+		req, _ := http.NewRequest(http.MethodGet, tc.URL, nil)
+		proxyURL, err := proxy(req)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tc.URL, err)
+		}
+
+		got := ""
+		if proxyURL != nil {
+			got = proxyURL.String()
+		}
+		if got != tc.Proxy {
+			t.Fatalf("got proxy %q expected %q for %q", got, tc.Proxy, tc.URL)
+		}
+	}
+}
+
+func TestGitlab_headerTransport(t *testing.T) {
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newHeaderTransport(http.DefaultTransport, map[string]string{
+		"X-Proxy-Auth":  "secret",
+		"Authorization": "Bearer custom",
+	})}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := gotHeader.Get("X-Proxy-Auth"); got != "secret" {
+		t.Fatalf("got X-Proxy-Auth header %q expected %q", got, "secret")
+	}
+	if got := gotHeader.Get("Authorization"); got != "Bearer token" {
+		t.Fatalf("got Authorization header %q expected it not to be overridden", got)
+	}
+}
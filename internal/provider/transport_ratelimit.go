@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// newRateLimitTransport wraps the given transport so that at most requestsPerSecond requests
+// are sent and at most maxConcurrentRequests requests are in-flight at the same time.
+// A value of zero disables the respective limit.
+//
+// The transport is shared by all resources and data sources of a provider instance,
+// thus all of them draw from the same token bucket.
+func newRateLimitTransport(transport http.RoundTripper, requestsPerSecond float64, maxConcurrentRequests int) http.RoundTripper {
+	if requestsPerSecond <= 0 && maxConcurrentRequests <= 0 {
+		return transport
+	}
+
+	t := &rateLimitTransport{transport: transport}
+	if requestsPerSecond > 0 {
+		t.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+	}
+	if maxConcurrentRequests > 0 {
+		t.inFlight = make(chan struct{}, maxConcurrentRequests)
+	}
+	return t
+}
+
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *rate.Limiter
+	inFlight  chan struct{}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.limiter != nil {
+		reservation := t.limiter.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			log.Printf("[DEBUG] GitLab API request %s %s delayed by %s due to client-side rate limit", req.Method, req.URL.Path, delay)
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				reservation.Cancel()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	if t.inFlight != nil {
+		select {
+		case t.inFlight <- struct{}{}:
+		default:
+			log.Printf("[DEBUG] GitLab API request %s %s delayed: %d requests already in-flight", req.Method, req.URL.Path, cap(t.inFlight))
+			select {
+			case t.inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if t.inFlight != nil {
+		// The request is in-flight until its response body has been consumed.
+		if err != nil || resp.Body == nil {
+			<-t.inFlight
+		} else {
+			resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: func() { <-t.inFlight }}
+		}
+	}
+	return resp, err
+}
+
+// releaseOnCloseBody calls release exactly once when the body is closed.
+type releaseOnCloseBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releaseOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
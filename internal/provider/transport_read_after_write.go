@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newReadAfterWriteTransport wraps the given transport, so that a `GET` request responding with a 404 is retried
+// for up to the given timeout, if it reads an object which has been written before by the same create or update of a resource.
+// GitLab persists some objects asynchronously, e.g. protected branches, members or access tokens,
+// thus reading them right after they have been written may fail.
+//
+// An object is considered written by a successful `POST`, `PUT` or `PATCH` request to its path,
+// or by a `POST` request to its collection, e.g. `POST /projects/42/members` writes `/projects/42/members/7`.
+// Other 404 responses, e.g. for optional objects like the push rules of a new project, are returned immediately.
+func newReadAfterWriteTransport(transport http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout <= 0 {
+		return transport
+	}
+	return &readAfterWriteTransport{transport: transport, timeout: timeout, waitMin: 250 * time.Millisecond, waitMax: 2 * time.Second}
+}
+
+type readAfterWriteTransport struct {
+	transport http.RoundTripper
+	timeout   time.Duration
+	waitMin   time.Duration
+	waitMax   time.Duration
+}
+
+type readAfterWriteContextKey struct{}
+
+// readAfterWriteState tracks the objects written with a context, see withReadAfterWrite.
+type readAfterWriteState struct {
+	mu          sync.Mutex
+	objects     []string
+	collections []string
+}
+
+// withReadAfterWrite returns a context in which the reads of written objects are retried by the readAfterWriteTransport.
+func withReadAfterWrite(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readAfterWriteContextKey{}, &readAfterWriteState{})
+}
+
+// withReadAfterWriteRetries wraps the create and update functions of the given resource, so that the reads
+// right after the objects have been written are retried by the readAfterWriteTransport.
+func withReadAfterWriteRetries(r *schema.Resource) *schema.Resource {
+	withWrites := func(fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if fn == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return fn(withReadAfterWrite(ctx), d, meta)
+		}
+	}
+	r.CreateContext = withWrites(r.CreateContext)
+	r.UpdateContext = withWrites(r.UpdateContext)
+	return r
+}
+
+func (t *readAfterWriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	state, ok := req.Context().Value(readAfterWriteContextKey{}).(*readAfterWriteState)
+	if !ok {
+		return t.transport.RoundTrip(req)
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	path := req.URL.EscapedPath()
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 && !strings.HasSuffix(path, "/api/graphql") {
+			state.written(req.Method, path)
+		}
+	case http.MethodGet:
+		if resp.StatusCode != http.StatusNotFound || !state.isWritten(path) {
+			return resp, nil
+		}
+
+		deadline := time.Now().Add(t.timeout)
+		for attempt := 0; resp.StatusCode == http.StatusNotFound; attempt++ {
+			wait := t.waitMin << attempt
+			if wait > t.waitMax || wait <= 0 {
+				wait = t.waitMax
+			}
+			if time.Now().Add(wait).After(deadline) {
+				break
+			}
+
+			log.Printf("[DEBUG] GitLab API request %s %s responded with 404 after the object has been written, retrying in %s", req.Method, req.URL.Path, wait)
+			resp.Body.Close()
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(wait):
+			}
+
+			if resp, err = t.transport.RoundTrip(req); err != nil {
+				return resp, err
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (s *readAfterWriteState) written(method string, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects = append(s.objects, path)
+	if method == http.MethodPost {
+		s.collections = append(s.collections, path)
+	}
+}
+
+func (s *readAfterWriteState) isWritten(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, object := range s.objects {
+		if path == object {
+			return true
+		}
+	}
+	for _, collection := range s.collections {
+		if rest := strings.TrimPrefix(path, collection+"/"); rest != path && rest != "" && !strings.Contains(rest, "/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGitlab_readAfterWriteTransport(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := r.Method + " " + r.URL.Path
+		requests[key]++
+		// Objects become readable with their third request.
+		if r.Method == http.MethodGet && requests[key] < 3 {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := &readAfterWriteTransport{transport: http.DefaultTransport, timeout: time.Second, waitMin: time.Millisecond, waitMax: 5 * time.Millisecond}
+	client := &http.Client{Transport: transport}
+	do := func(ctx context.Context, method, path string) int {
+		req, _ := http.NewRequestWithContext(ctx, method, server.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: unexpected error: %v", method, path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	cases := []struct {
+		Name         string
+		Writes       []string
+		Read         string
+		WantStatus   int
+		WantRequests int
+	}{
+		{Name: "no write", Read: "/api/v4/projects/1", WantStatus: 404, WantRequests: 1},
+		{Name: "created in collection", Writes: []string{"POST /api/v4/projects/2/members"}, Read: "/api/v4/projects/2/members/7", WantStatus: 200, WantRequests: 3},
+		{Name: "updated", Writes: []string{"PUT /api/v4/projects/3/variables/FOO"}, Read: "/api/v4/projects/3/variables/FOO", WantStatus: 200, WantRequests: 3},
+		{Name: "below created object", Writes: []string{"POST /api/v4/projects"}, Read: "/api/v4/projects/4/push_rule", WantStatus: 404, WantRequests: 1},
+		{Name: "below updated object", Writes: []string{"PUT /api/v4/projects/5"}, Read: "/api/v4/projects/5/push_rule", WantStatus: 404, WantRequests: 1},
+	}
+
+	for _, tc := range cases {
+		ctx := withReadAfterWrite(context.Background())
+		for _, write := range tc.Writes {
+			parts := strings.SplitN(write, " ", 2)
+			do(ctx, parts[0], parts[1])
+		}
+		if status := do(ctx, http.MethodGet, tc.Read); status != tc.WantStatus {
+			t.Fatalf("%s: got status %d expected %d", tc.Name, status, tc.WantStatus)
+		}
+		if got := requests["GET "+tc.Read]; got != tc.WantRequests {
+			t.Fatalf("%s: got %d requests expected %d", tc.Name, got, tc.WantRequests)
+		}
+	}
+
+	// Without a write in its own context, a read isn't retried.
+	do(withReadAfterWrite(context.Background()), http.MethodPost, "/api/v4/groups/6/members")
+	if status := do(withReadAfterWrite(context.Background()), http.MethodGet, "/api/v4/groups/6/members/7"); status != http.StatusNotFound {
+		t.Fatalf("got status %d expected 404", status)
+	}
+
+	// The retries end with the timeout.
+	transport.timeout = 2 * time.Millisecond
+	transport.waitMin, transport.waitMax = 5*time.Millisecond, 5*time.Millisecond
+	ctx := withReadAfterWrite(context.Background())
+	do(ctx, http.MethodPut, "/api/v4/projects/8")
+	if status := do(ctx, http.MethodGet, "/api/v4/projects/8"); status != http.StatusNotFound || requests["GET /api/v4/projects/8"] != 1 {
+		t.Fatalf("got status %d after %d requests, expected a single 404", status, requests["GET /api/v4/projects/8"])
+	}
+}
+
+func TestGitlab_withReadAfterWriteRetries(t *testing.T) {
+	retried := map[string]bool{}
+	record := func(name string) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			_, retried[name] = ctx.Value(readAfterWriteContextKey{}).(*readAfterWriteState)
+			return nil
+		}
+	}
+	r := withReadAfterWriteRetries(&schema.Resource{
+		CreateContext: record("create"),
+		ReadContext:   record("read"),
+		UpdateContext: record("update"),
+		DeleteContext: record("delete"),
+	})
+
+	ctx := context.Background()
+	r.CreateContext(ctx, nil, nil)
+	r.ReadContext(ctx, nil, nil)
+	r.UpdateContext(ctx, nil, nil)
+	r.DeleteContext(ctx, nil, nil)
+
+	expected := map[string]bool{"create": true, "read": false, "update": true, "delete": false}
+	for name, want := range expected {
+		if retried[name] != want {
+			t.Fatalf("%s: got read after write retries %t expected %t", name, retried[name], want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// newReadOnlyTransport wraps the given transport so that it rejects every request which may modify
+// data in GitLab. Only `GET`, `HEAD` and `OPTIONS` requests and GraphQL queries are sent.
+func newReadOnlyTransport(transport http.RoundTripper) http.RoundTripper {
+	return &readOnlyTransport{transport: transport}
+}
+
+type readOnlyTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.transport.RoundTrip(req)
+	case http.MethodPost:
+		if strings.HasSuffix(req.URL.Path, "/api/graphql") && req.Body != nil {
+			body, err := io.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			// A RoundTripper must not modify the given request, thus the consumed body is set on a copy.
+			req = req.Clone(req.Context())
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			if isGraphQLQuery(body) {
+				return t.transport.RoundTrip(req)
+			}
+		}
+	}
+
+	caller := "the provider"
+	if resourceType := resourceTypeFromContext(req.Context()); resourceType != "" {
+		caller = resourceType
+	}
+	return nil, fmt.Errorf("the GitLab provider is configured with `read_only = true`, but %s attempted a mutating API call: %s %s", caller, req.Method, req.URL.Path)
+}
+
+// isGraphQLQuery checks if the given GraphQL request body only contains query operations.
+// The body is either a single request or a batch of requests, see isGraphQLQueryDocument.
+func isGraphQLQuery(body []byte) bool {
+	type request struct {
+		Query string `json:"query"`
+	}
+
+	var requests []request
+	if err := json.Unmarshal(body, &requests); err != nil {
+		var single request
+		if err := json.Unmarshal(body, &single); err != nil {
+			return false
+		}
+		requests = []request{single}
+	}
+
+	for _, r := range requests {
+		if !isGraphQLQueryDocument(r.Query) {
+			return false
+		}
+	}
+	return len(requests) > 0
+}
+
+// isGraphQLQueryDocument checks if the given GraphQL document only contains query operations.
+// The document is tokenized, skipping comments, strings and block strings, and the first name
+// of every top-level definition must not be `mutation` or `subscription`.
+// Documents which can't be tokenized are not considered to be queries.
+func isGraphQLQueryDocument(document string) bool {
+	braces, parens := 0, 0
+	definitionStart := true
+
+	for i := 0; i < len(document); {
+		c := document[i]
+		switch {
+		case c == '#':
+			for i < len(document) && document[i] != '\n' && document[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(document[i:], `"""`):
+			end := -1
+			for j := i + 3; j+3 <= len(document); j++ {
+				if strings.HasPrefix(document[j:], `\"""`) {
+					j += 3
+					continue
+				}
+				if strings.HasPrefix(document[j:], `"""`) {
+					end = j + 3
+					break
+				}
+			}
+			if end < 0 {
+				return false
+			}
+			i = end
+		case c == '"':
+			i++
+			for i < len(document) && document[i] != '"' {
+				if document[i] == '\n' || document[i] == '\r' {
+					return false
+				}
+				if document[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(document) {
+				return false
+			}
+			i++
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			start := i
+			for i < len(document) && (document[i] == '_' || (document[i] >= 'a' && document[i] <= 'z') || (document[i] >= 'A' && document[i] <= 'Z') || (document[i] >= '0' && document[i] <= '9')) {
+				i++
+			}
+			if definitionStart && braces == 0 && parens == 0 {
+				switch document[start:i] {
+				case "mutation", "subscription":
+					return false
+				}
+				definitionStart = false
+			}
+		default:
+			switch c {
+			case '{':
+				// A selection set without an operation type is a query.
+				definitionStart = false
+				braces++
+			case '}':
+				braces--
+				definitionStart = braces == 0 && parens == 0
+			case '(':
+				parens++
+			case ')':
+				parens--
+			}
+			if braces < 0 || parens < 0 {
+				return false
+			}
+			i++
+		}
+	}
+	return braces == 0 && parens == 0
+}
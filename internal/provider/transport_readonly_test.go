@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitlab_readOnlyTransport(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newReadOnlyTransport(http.DefaultTransport)}
+
+	cases := []struct {
+		Method  string
+		Path    string
+		Body    string
+		Allowed bool
+	}{
+		{Method: http.MethodGet, Path: "/api/v4/projects/1", Allowed: true},
+		{Method: http.MethodPost, Path: "/api/v4/projects", Body: `{"name": "foo"}`, Allowed: false},
+		{Method: http.MethodPut, Path: "/api/v4/projects/1", Body: `{"name": "foo"}`, Allowed: false},
+		{Method: http.MethodDelete, Path: "/api/v4/projects/1", Allowed: false},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "query { currentUser { name } }"}`, Allowed: true},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "{ currentUser { name } }"}`, Allowed: true},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "# mutation\nquery { currentUser { name } }"}`, Allowed: true},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "mutation { createNote(input: {}) { errors } }"}`, Allowed: false},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "query a { currentUser { name } } mutation b { createNote(input: {}) { errors } }"}`, Allowed: false},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "query { p(x: \"#\") { id } } mutation { destroy { id } }"}`, Allowed: false},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "query { p(x: \"\"\"}\n# \\\"\"\" }\"\"\") { id } } mutation { destroy { id } }"}`, Allowed: false},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `{"query": "query { p(x: \"} mutation {\") { id } }"}`, Allowed: true},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `[{"query": "{ currentUser { name } }"}, {"query": "query { p { id } }"}]`, Allowed: true},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `[{"query": "{ currentUser { name } }"}, {"query": "mutation { destroy { id } }"}]`, Allowed: false},
+		{Method: http.MethodPost, Path: "/api/graphql", Body: `query=mutation`, Allowed: false},
+	}
+
+	for _, tc := range cases {
+		gotBody = ""
+		ctx := context.WithValue(context.Background(), resourceTypeContextKey{}, "gitlab_project")
+		req, _ := http.NewRequestWithContext(ctx, tc.Method, server.URL+tc.Path, strings.NewReader(tc.Body))
+		resp, err := client.Do(req)
+
+		if tc.Allowed {
+			if err != nil {
+				t.Fatalf("%s %s with body %q: unexpected error: %v", tc.Method, tc.Path, tc.Body, err)
+			}
+			resp.Body.Close()
+			if gotBody != tc.Body {
+				t.Fatalf("%s %s: got body %q expected %q", tc.Method, tc.Path, gotBody, tc.Body)
+			}
+			continue
+		}
+
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("%s %s with body %q: expected request to be rejected", tc.Method, tc.Path, tc.Body)
+		}
+		if !strings.Contains(err.Error(), "gitlab_project attempted a mutating API call: "+tc.Method+" "+tc.Path) {
+			t.Fatalf("%s %s: got error %q expected it to name the resource and endpoint", tc.Method, tc.Path, err)
+		}
+	}
+}
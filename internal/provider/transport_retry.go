@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+type retryRequestMethodContextKey struct{}
+
+// newRetryTransport wraps the given transport so that failed requests are retried.
+//
+// Requests are retried when GitLab responds with 429 Too Many Requests. Requests
+// with an idempotent method are additionally retried on connection errors and
+// on 502, 503 and 504 responses. The wait between attempts honors the `Retry-After`
+// and `RateLimit-Reset` response headers up to waitMax and otherwise backs off exponentially
+// between waitMin and waitMax.
+func newRetryTransport(transport http.RoundTripper, maxRetries int, waitMin, waitMax time.Duration) http.RoundTripper {
+	client := &retryablehttp.Client{
+		HTTPClient: &http.Client{
+			Transport: transport,
+			// Redirects are followed by the client using this transport, not by the transport itself.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		RetryMax:     maxRetries,
+		RetryWaitMin: waitMin,
+		RetryWaitMax: waitMax,
+		CheckRetry:   retryPolicy,
+		Backoff:      retryBackoff,
+		// Hand the last response to the go-gitlab client, so that it can turn it into a proper `*gitlab.ErrorResponse`.
+		ErrorHandler: retryablehttp.PassthroughErrorHandler,
+		RequestLogHook: func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+			if attempt > 0 {
+				log.Printf("[DEBUG] GitLab API request %s %s: retry attempt %d of %d", req.Method, req.URL.Path, attempt, maxRetries)
+			}
+		},
+	}
+
+	return &retryTransport{rt: &retryablehttp.RoundTripper{Client: client}}
+}
+
+type retryTransport struct {
+	rt http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The retry policy only has access to the request context in case of connection errors,
+	// therefore we carry the request method in it.
+	ctx := context.WithValue(req.Context(), retryRequestMethodContextKey{}, req.Method)
+	return t.rt.RoundTrip(req.WithContext(ctx))
+}
+
+// retryPolicy is a retryablehttp.CheckRetry which decides if a request should be retried.
+func retryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	method, _ := ctx.Value(retryRequestMethodContextKey{}).(string)
+	if err != nil {
+		return isIdempotentMethod(method), nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// A rate limited request has not been processed by GitLab, thus it's safe to retry for any method.
+		return true, nil
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotentMethod(method), nil
+	}
+
+	return false, nil
+}
+
+// retryBackoff is a retryablehttp.Backoff which waits for as long as GitLab tells us to,
+// using the `Retry-After` or `RateLimit-Reset` headers, but at most max, so that a misbehaving
+// server can't stall the provider. If none of these headers are present it backs off exponentially,
+// bounded by min and max.
+func retryBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfterFromHeaders(resp.Header, time.Now()); ok {
+			if wait < min {
+				return min
+			}
+			if wait > max {
+				return max
+			}
+			return wait
+		}
+	}
+
+	wait := float64(min) * math.Pow(2, float64(attemptNum))
+	if wait > float64(max) {
+		return max
+	}
+	return time.Duration(wait)
+}
+
+// retryAfterFromHeaders returns the duration to wait as requested by the `Retry-After` header,
+// which is either a number of seconds or an HTTP date, or by GitLab's `RateLimit-Reset` header,
+// which is a unix timestamp.
+func retryAfterFromHeaders(header http.Header, now time.Time) (time.Duration, bool) {
+	if v := header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.ParseInt(v, 10, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(v); err == nil {
+			return date.Sub(now), true
+		}
+	}
+
+	if v := header.Get("RateLimit-Reset"); v != "" {
+		if reset, err := strconv.ParseInt(v, 10, 64); err == nil && reset > 0 {
+			return time.Unix(reset, 0).Sub(now), true
+		}
+	}
+
+	return 0, false
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
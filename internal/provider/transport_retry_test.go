@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGitlab_retryTransport(t *testing.T) {
+	cases := []struct {
+		Method       string
+		Status       int
+		WantAttempts int32
+	}{
+		{Method: http.MethodGet, Status: http.StatusBadGateway, WantAttempts: 3},
+		{Method: http.MethodPut, Status: http.StatusServiceUnavailable, WantAttempts: 3},
+		{Method: http.MethodPost, Status: http.StatusBadGateway, WantAttempts: 1},
+		{Method: http.MethodPost, Status: http.StatusTooManyRequests, WantAttempts: 3},
+		{Method: http.MethodGet, Status: http.StatusInternalServerError, WantAttempts: 1},
+		{Method: http.MethodGet, Status: http.StatusNotFound, WantAttempts: 1},
+	}
+
+	for _, tc := range cases {
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.WriteHeader(tc.Status)
+		}))
+
+		client := &http.Client{Transport: newRetryTransport(http.DefaultTransport, 2, time.Millisecond, time.Millisecond)}
+		req, _ := http.NewRequest(tc.Method, server.URL, strings.NewReader("{}"))
+		resp, err := client.Do(req)
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s with status %d: unexpected error: %v", tc.Method, tc.Status, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != tc.Status {
+			t.Fatalf("%s with status %d: got final status %d", tc.Method, tc.Status, resp.StatusCode)
+		}
+		if attempts != tc.WantAttempts {
+			t.Fatalf("%s with status %d: got %d attempts, expected %d", tc.Method, tc.Status, attempts, tc.WantAttempts)
+		}
+	}
+}
+
+func TestGitlab_retryAfterFromHeaders(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		Header http.Header
+		Wait   time.Duration
+		Ok     bool
+	}{
+		{
+			Header: http.Header{"Retry-After": []string{"42"}},
+			Wait:   42 * time.Second,
+			Ok:     true,
+		},
+		{
+			Header: http.Header{"Retry-After": []string{now.Add(time.Minute).Format(http.TimeFormat)}},
+			Wait:   time.Minute,
+			Ok:     true,
+		},
+		{
+			Header: http.Header{"Ratelimit-Reset": []string{"1662033630"}},
+			Wait:   30 * time.Second,
+			Ok:     true,
+		},
+		{
+			Header: http.Header{},
+			Ok:     false,
+		},
+	}
+
+	for _, tc := range cases {
+		wait, ok := retryAfterFromHeaders(tc.Header, now)
+		if ok != tc.Ok || wait != tc.Wait {
+			t.Fatalf("got (%v, %v) expected (%v, %v) for headers %v", wait, ok, tc.Wait, tc.Ok, tc.Header)
+		}
+	}
+}
+
+func TestGitlab_retryBackoff(t *testing.T) {
+	cases := []struct {
+		RetryAfter string
+		Wait       time.Duration
+	}{
+		{RetryAfter: "0", Wait: time.Second},
+		{RetryAfter: "5", Wait: 5 * time.Second},
+		{RetryAfter: "86400", Wait: 30 * time.Second},
+	}
+
+	for _, tc := range cases {
+		resp := &http.Response{Header: http.Header{"Retry-After": []string{tc.RetryAfter}}}
+		if wait := retryBackoff(time.Second, 30*time.Second, 0, resp); wait != tc.Wait {
+			t.Fatalf("got %v expected %v for Retry-After %s", wait, tc.Wait, tc.RetryAfter)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newTokenTransport wraps the given transport so that every request is authenticated
+// with a Bearer token retrieved from the given token source.
+func newTokenTransport(transport http.RoundTripper, source oauth2.TokenSource) http.RoundTripper {
+	return &tokenTransport{transport: transport, source: source}
+}
+
+type tokenTransport struct {
+	transport http.RoundTripper
+	source    oauth2.TokenSource
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve GitLab token: %w", err)
+	}
+
+	// A RoundTripper must not modify the given request.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return t.transport.RoundTrip(req)
+}
+
+// tokenFileSource is an oauth2.TokenSource which reads the token from a file every time
+// it's requested, so that a rotated token is picked up without restarting the provider.
+type tokenFileSource struct {
+	path string
+}
+
+func (s *tokenFileSource) Token() (*oauth2.Token, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return nil, fmt.Errorf("token file %q is empty", s.path)
+	}
+	return &oauth2.Token{AccessToken: token}, nil
+}
+
+// tokenCommandSource is an oauth2.TokenSource which executes a credential helper command.
+//
+// The command either prints the plain token or a JSON object like
+// `{"token": "glpat-...", "expires_at": "2022-09-01T12:00:00Z"}` to stdout.
+// It's meant to be wrapped with oauth2.ReuseTokenSource, so that the command is only
+// executed again once the token has expired.
+type tokenCommandSource struct {
+	command []string
+	// timeout is the maximum time the command may run, defaults to tokenCommandTimeout.
+	timeout time.Duration
+}
+
+// tokenCommandTimeout is the default maximum time a token command may run, so that a hanging
+// credential helper, e.g. one waiting for interactive input, doesn't block the provider forever.
+const tokenCommandTimeout = 1 * time.Minute
+
+type tokenCommandOutput struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s *tokenCommandSource) Token() (*oauth2.Token, error) {
+	timeout := s.timeout
+	if timeout == 0 {
+		timeout = tokenCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.command[0], s.command[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("token command %q did not finish within %s: %s", s.command[0], timeout, strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("token command %q failed: %w: %s", s.command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseTokenCommandOutput(stdout)
+}
+
+func parseTokenCommandOutput(stdout []byte) (*oauth2.Token, error) {
+	output := bytes.TrimSpace(stdout)
+	if bytes.HasPrefix(output, []byte("{")) {
+		var parsed tokenCommandOutput
+		if err := json.Unmarshal(output, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON output of token command: %w", err)
+		}
+		if parsed.Token == "" {
+			return nil, fmt.Errorf("JSON output of token command is missing the `token` field")
+		}
+		return &oauth2.Token{AccessToken: parsed.Token, Expiry: parsed.ExpiresAt}, nil
+	}
+
+	if len(output) == 0 {
+		return nil, fmt.Errorf("token command did not print a token")
+	}
+	return &oauth2.Token{AccessToken: string(output)}, nil
+}
+
+// newOAuthRefreshTokenSource returns an oauth2.TokenSource which uses the OAuth2 refresh token flow
+// of the GitLab instance at baseURL to retrieve a fresh access token whenever the current one expires.
+// The token requests are made with the given HTTP client, so that they use the same TLS settings as
+// the API requests.
+func newOAuthRefreshTokenSource(baseURL string, httpClient *http.Client, clientID, clientSecret, refreshToken string) (oauth2.TokenSource, error) {
+	tokenURL, err := oauthTokenURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  tokenURL,
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+
+	// The context is used for all token refreshes during the lifetime of the provider,
+	// thus it must not be bound to the context of a single request.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	return config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}), nil
+}
+
+// oauthTokenURL returns the URL of the OAuth2 token endpoint for the given API base URL,
+// e.g. `https://gitlab.example.com/gitlab/api/v4/` -> `https://gitlab.example.com/gitlab/oauth/token`
+func oauthTokenURL(baseURL string) (string, error) {
+	if baseURL == "" {
+		baseURL = "https://gitlab.com/"
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse base url %q: %w", baseURL, err)
+	}
+
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v4") + "/oauth/token"
+	return u.String(), nil
+}
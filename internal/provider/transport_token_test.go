@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitlab_tokenTransport_tokenFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+
+	var gotAuthorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newTokenTransport(http.DefaultTransport, &tokenFileSource{path: tokenFile})}
+
+	// The token file is re-read for every request, thus a rotated token is picked up.
+	for _, token := range []string{"first-token", "rotated-token"} {
+		if err := os.WriteFile(tokenFile, []byte(token+"\n"), 0600); err != nil {
+			t.Fatalf("failed to write token file: %v", err)
+		}
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if gotAuthorization != "Bearer "+token {
+			t.Fatalf("got Authorization header %q expected %q", gotAuthorization, "Bearer "+token)
+		}
+	}
+}
+
+func TestGitlab_parseTokenCommandOutput(t *testing.T) {
+	cases := []struct {
+		Output string
+		Token  string
+		Expiry time.Time
+	}{
+		{
+			Output: "glpat-plain\n",
+			Token:  "glpat-plain",
+		},
+		{
+			Output: `{"token": "glpat-json", "expires_at": "2022-09-01T12:00:00Z"}`,
+			Token:  "glpat-json",
+			Expiry: time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		token, err := parseTokenCommandOutput([]byte(tc.Output))
+		if err != nil {
+			t.Fatalf("unexpected error for output %q: %v", tc.Output, err)
+		}
+		if token.AccessToken != tc.Token || !token.Expiry.Equal(tc.Expiry) {
+			t.Fatalf("got (%q, %v) expected (%q, %v)", token.AccessToken, token.Expiry, tc.Token, tc.Expiry)
+		}
+	}
+
+	for _, output := range []string{"", "  \n", `{"expires_at": "2022-09-01T12:00:00Z"}`, `{"token":`} {
+		if _, err := parseTokenCommandOutput([]byte(output)); err == nil {
+			t.Fatalf("expected error for output %q", output)
+		}
+	}
+}
+
+func TestGitlab_tokenCommandSource(t *testing.T) {
+	token, err := (&tokenCommandSource{command: []string{"sh", "-c", "echo glpat-command"}}).Token()
+	if err != nil || token.AccessToken != "glpat-command" {
+		t.Fatalf("got token %v and error %v expected %q", token, err, "glpat-command")
+	}
+
+	// The error of a failing command contains what it printed to stderr.
+	_, err = (&tokenCommandSource{command: []string{"sh", "-c", "echo 'not logged in' >&2; exit 1"}}).Token()
+	if err == nil || !strings.Contains(err.Error(), "not logged in") {
+		t.Fatalf("expected an error with the stderr of the command, got %v", err)
+	}
+
+	// A hanging command is killed after the timeout.
+	_, err = (&tokenCommandSource{command: []string{"sleep", "10"}, timeout: 100 * time.Millisecond}).Token()
+	if err == nil || !strings.Contains(err.Error(), "did not finish within 100ms") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+}
+
+func TestGitlab_oauthTokenURL(t *testing.T) {
+	cases := []struct {
+		BaseURL  string
+		TokenURL string
+	}{
+		{
+			BaseURL:  "",
+			TokenURL: "https://gitlab.com/oauth/token",
+		},
+		{
+			BaseURL:  "https://gitlab.example.com/api/v4/",
+			TokenURL: "https://gitlab.example.com/oauth/token",
+		},
+		{
+			BaseURL:  "https://example.com/gitlab/api/v4",
+			TokenURL: "https://example.com/gitlab/oauth/token",
+		},
+		{
+			BaseURL:  "https://gitlab.example.com/",
+			TokenURL: "https://gitlab.example.com/oauth/token",
+		},
+	}
+
+	for _, tc := range cases {
+		tokenURL, err := oauthTokenURL(tc.BaseURL)
+		if err != nil {
+			t.Fatalf("unexpected error for base url %q: %v", tc.BaseURL, err)
+		}
+		if tokenURL != tc.TokenURL {
+			t.Fatalf("got %q expected %q for base url %q", tokenURL, tc.TokenURL, tc.BaseURL)
+		}
+	}
+}
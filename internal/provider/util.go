@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-cty/cty"
@@ -59,6 +60,21 @@ var validateURLFunc = func(v interface{}, k string) (s []string, errors []error)
 	return
 }
 
+var validateDurationFunc = func(v interface{}, k string) (s []string, errors []error) {
+	value := v.(string)
+	if d, err := time.ParseDuration(value); err != nil || d < 0 {
+		errors = append(errors, fmt.Errorf("%s is not a valid duration for %s, e.g. `500ms`, `2s` or `1m`", value, k))
+	}
+	return
+}
+
+var validateCertificatesPEMFunc = func(v interface{}, k string) (s []string, errors []error) {
+	if _, err := parseCertificatesPEM([]byte(v.(string))); err != nil {
+		errors = append(errors, fmt.Errorf("%s is not a valid PEM encoded certificate chain: %w", k, err))
+	}
+	return
+}
+
 func stringToVisibilityLevel(s string) *gitlab.VisibilityValue {
 	lookup := map[string]gitlab.VisibilityValue{
 		"private":  gitlab.PrivateVisibility,
@@ -283,6 +299,14 @@ func fromIntegerMap(value interface{}) map[string]int {
 	return integerMap
 }
 
+func fromStringMap(value interface{}) map[string]string {
+	stringMap := make(map[string]string)
+	for k, v := range value.(map[string]interface{}) {
+		stringMap[k] = v.(string)
+	}
+	return stringMap
+}
+
 // isGitLabVersionLessThan is a SkipFunc that returns true if the provided version is lower then
 // the current version of GitLab. It only checks the major and minor version numbers, not the patch.
 func isGitLabVersionLessThan(ctx context.Context, client *gitlab.Client, version string) func() (bool, error) {
@@ -296,27 +320,33 @@ func isGitLabVersionLessThan(ctx context.Context, client *gitlab.Client, version
 // provided wantVersion. It only checks the major and minor version numbers, not the patch.
 func isGitLabVersionAtLeast(ctx context.Context, client *gitlab.Client, wantVersion string) func() (bool, error) {
 	return func() (bool, error) {
-		wantMajor, wantMinor, err := parseVersionMajorMinor(wantVersion)
-		if err != nil {
-			return false, fmt.Errorf("failed to parse wanted version %q: %w", wantVersion, err)
-		}
-
 		actualVersion, _, err := client.Version.GetVersion(gitlab.WithContext(ctx))
 		if err != nil {
 			return false, err
 		}
 
-		actualMajor, actualMinor, err := parseVersionMajorMinor(actualVersion.Version)
-		if err != nil {
-			return false, fmt.Errorf("failed to parse actual version %q: %w", actualVersion.Version, err)
-		}
+		return isVersionAtLeast(actualVersion.Version, wantVersion)
+	}
+}
 
-		if actualMajor == wantMajor {
-			return actualMinor >= wantMinor, nil
-		}
+// isVersionAtLeast checks that the actualVersion is at least the provided wantVersion.
+// It only checks the major and minor version numbers, not the patch.
+func isVersionAtLeast(actualVersion, wantVersion string) (bool, error) {
+	wantMajor, wantMinor, err := parseVersionMajorMinor(wantVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse wanted version %q: %w", wantVersion, err)
+	}
+
+	actualMajor, actualMinor, err := parseVersionMajorMinor(actualVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse actual version %q: %w", actualVersion, err)
+	}
 
-		return actualMajor > wantMajor, nil
+	if actualMajor == wantMajor {
+		return actualMinor >= wantMinor, nil
 	}
+
+	return actualMajor > wantMajor, nil
 }
 
 func parseVersionMajorMinor(version string) (int, int, error) {
@@ -347,13 +377,13 @@ func is404(err error) bool {
 	return false
 }
 
-func isCurrentUserAdmin(ctx context.Context, client *gitlab.Client) (bool, error) {
-	currentUser, _, err := client.Users.CurrentUser(gitlab.WithContext(ctx))
-	if err != nil {
-		return false, err
+func is400(err error) bool {
+	if errResponse, ok := err.(*gitlab.ErrorResponse); ok &&
+		errResponse.Response != nil &&
+		errResponse.Response.StatusCode == 400 {
+		return true
 	}
-
-	return currentUser.IsAdmin, nil
+	return false
 }
 
 // ISO 8601 date format
@@ -498,3 +528,60 @@ func (c lock) lock(ctx context.Context) error {
 func (c lock) unlock() {
 	<-c
 }
+
+// keyedLock is a registry of `context.Context` aware locks, one for every key.
+// It can be used to serialize API calls which conflict with each other for the same
+// object, e.g. commits to the same branch, without serializing the calls for all other objects.
+// The lock of a key is removed from the registry once nobody holds or waits for it anymore.
+type keyedLock struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLockEntry
+}
+
+type keyedLockEntry struct {
+	lock lock
+	// refs is the number of callers which hold or wait for the lock.
+	refs int
+}
+
+func newKeyedLock() *keyedLock {
+	return &keyedLock{locks: make(map[string]*keyedLockEntry)}
+}
+
+func (k *keyedLock) lock(ctx context.Context, key string) error {
+	k.mu.Lock()
+	entry, ok := k.locks[key]
+	if !ok {
+		entry = &keyedLockEntry{lock: newLock()}
+		k.locks[key] = entry
+	}
+	entry.refs++
+	k.mu.Unlock()
+
+	if err := entry.lock.lock(ctx); err != nil {
+		k.release(key)
+		return err
+	}
+	return nil
+}
+
+func (k *keyedLock) unlock(key string) {
+	k.mu.Lock()
+	entry := k.locks[key]
+	k.mu.Unlock()
+
+	entry.lock.unlock()
+	k.release(key)
+}
+
+// release drops a reference to the lock of the given key and removes it if it was the last one.
+func (k *keyedLock) release(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	entry := k.locks[key]
+	entry.refs--
+	if entry.refs == 0 {
+		delete(k.locks, key)
+	}
+}
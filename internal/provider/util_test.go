@@ -1,7 +1,9 @@
 package provider
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	gitlab "github.com/xanzy/go-gitlab"
 )
@@ -119,3 +121,35 @@ func TestValidateURLFunc(t *testing.T) {
 		}
 	}
 }
+
+func TestGitlab_keyedLock(t *testing.T) {
+	locks := newKeyedLock()
+
+	if err := locks.lock(context.Background(), "project:1:branch:main"); err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+
+	// A different key must not be blocked by the held lock.
+	if err := locks.lock(context.Background(), "project:1:branch:feature"); err != nil {
+		t.Fatalf("failed to acquire lock for different key: %v", err)
+	}
+	locks.unlock("project:1:branch:feature")
+
+	// The same key must be blocked until the lock is released.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := locks.lock(ctx, "project:1:branch:main"); err == nil {
+		t.Fatalf("expected lock for the same key to time out")
+	}
+
+	locks.unlock("project:1:branch:main")
+	if err := locks.lock(context.Background(), "project:1:branch:main"); err != nil {
+		t.Fatalf("failed to acquire released lock: %v", err)
+	}
+
+	// The locks are removed once they aren't used anymore.
+	locks.unlock("project:1:branch:main")
+	if len(locks.locks) != 0 {
+		t.Fatalf("expected all unused locks to be removed, got %d", len(locks.locks))
+	}
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
+	"os"
 
 	"github.com/gitlabhq/terraform-provider-gitlab/internal/provider"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
@@ -15,10 +18,29 @@ var (
 
 func main() {
 	var debugMode bool
+	var generateGroup, generateDir string
+	var generateImportScript bool
 
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.StringVar(&generateGroup, "generate", "", "the ID or full path of an existing group to generate the Terraform configuration and imports for, instead of running the provider. The GITLAB_TOKEN and GITLAB_BASE_URL environment variables configure the connection")
+	flag.StringVar(&generateDir, "generate-dir", ".", "the directory the generated files are written to")
+	flag.BoolVar(&generateImportScript, "generate-import-script", false, "set to true to generate a script with `terraform import` commands instead of `import` blocks")
 	flag.Parse()
 
+	if generateGroup != "" {
+		config := provider.Config{
+			Token:         os.Getenv("GITLAB_TOKEN"),
+			BaseURL:       os.Getenv("GITLAB_BASE_URL"),
+			EarlyAuthFail: true,
+			ReadOnly:      true,
+		}
+		opts := provider.GenerateOptions{Config: config, Group: generateGroup, Dir: generateDir, ImportScript: generateImportScript}
+		if err := provider.Generate(context.Background(), opts); err != nil {
+			log.Fatalf("failed to generate the configuration for group %s: %v", generateGroup, err)
+		}
+		return
+	}
+
 	opts := &plugin.ServeOpts{ProviderFunc: provider.New(version), Debug: debugMode, ProviderAddr: "registry.terraform.io/providers/gitlabhq/gitlab"}
 	plugin.Serve(opts)
 }